@@ -0,0 +1,25 @@
+// Package v1alpha1 contains the kubegram operator's v1alpha1 API types,
+// currently just MCPProxy.
+// +kubebuilder:object:generate=true
+// +groupName=kubegram.io
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+var (
+	// GroupVersion is the group/version used to register these types.
+	GroupVersion = schema.GroupVersion{Group: "kubegram.io", Version: "v1alpha1"}
+
+	// SchemeBuilder is used to add go types to the GroupVersionKind scheme.
+	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
+
+	// AddToScheme adds the types in this group-version to the given scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+func init() {
+	SchemeBuilder.Register(&MCPProxy{}, &MCPProxyList{})
+}
@@ -0,0 +1,118 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto copies the receiver into out.
+//
+// Hand-written instead of controller-gen generated: this tree has no
+// Makefile/codegen pipeline wired up for `make manifests`, so these methods
+// are kept here rather than in a zz_generated.deepcopy.go that nothing
+// actually regenerates.
+func (in *MCPProxySpec) DeepCopyInto(out *MCPProxySpec) {
+	*out = *in
+	if in.Command != nil {
+		out.Command = append([]string(nil), in.Command...)
+	}
+	if in.Env != nil {
+		out.Env = make([]corev1.EnvVar, len(in.Env))
+		for i := range in.Env {
+			in.Env[i].DeepCopyInto(&out.Env[i])
+		}
+	}
+	if in.AuthSecretRef != nil {
+		out.AuthSecretRef = in.AuthSecretRef.DeepCopy()
+	}
+	if in.Enabled != nil {
+		enabled := *in.Enabled
+		out.Enabled = &enabled
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *MCPProxySpec) DeepCopy() *MCPProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *MCPProxyStatus) DeepCopyInto(out *MCPProxyStatus) {
+	*out = *in
+	if in.Tools != nil {
+		out.Tools = append([]string(nil), in.Tools...)
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *MCPProxyStatus) DeepCopy() *MCPProxyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPProxyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *MCPProxy) DeepCopyInto(out *MCPProxy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *MCPProxy) DeepCopy() *MCPProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *MCPProxy) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *MCPProxyList) DeepCopyInto(out *MCPProxyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]MCPProxy, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *MCPProxyList) DeepCopy() *MCPProxyList {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPProxyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *MCPProxyList) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	return in.DeepCopy()
+}
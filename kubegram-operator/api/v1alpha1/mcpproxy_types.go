@@ -0,0 +1,117 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MCPProxyTransport is the wire protocol the operator uses to reach an
+// MCPProxy's upstream MCP server.
+type MCPProxyTransport string
+
+const (
+	// MCPProxyTransportStdio spawns Spec.Command as a subprocess and speaks
+	// MCP over its stdin/stdout, mirroring --argo-mcp-cmd/--k8s-mcp-cmd.
+	MCPProxyTransportStdio MCPProxyTransport = "stdio"
+	// MCPProxyTransportSSE reaches Spec.URL over Server-Sent Events,
+	// mirroring --argo-mcp-url/--k8s-mcp-url.
+	MCPProxyTransportSSE MCPProxyTransport = "sse"
+	// MCPProxyTransportWebSocket reaches Spec.URL over a websocket.
+	MCPProxyTransportWebSocket MCPProxyTransport = "websocket"
+)
+
+// MCPProxySpec describes one upstream MCP server the operator should proxy
+// tools from.
+type MCPProxySpec struct {
+	// Transport selects how the operator reaches the upstream server.
+	// +kubebuilder:validation:Enum=stdio;sse;websocket
+	Transport MCPProxyTransport `json:"transport"`
+
+	// Command is the subprocess to run for transport: stdio. Command[0] is
+	// the executable, the rest are its arguments (e.g.
+	// ["npx", "-y", "@argoproj-labs/mcp-for-argocd"]).
+	// +optional
+	Command []string `json:"command,omitempty"`
+
+	// Env sets additional environment variables on the Command subprocess,
+	// for transport: stdio.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// URL is the upstream endpoint for transport: sse or websocket (e.g.
+	// "http://argo-mcp:8080/sse" or "ws://k8s-mcp:8080/ws").
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// AuthSecretRef, if set, names a key in a Secret in the same namespace
+	// holding a bearer token for the upstream server. The token is injected
+	// as MCP_PROXY_TOKEN in the stdio subprocess's environment, or as an
+	// "Authorization: Bearer <token>" header for sse and websocket. Key
+	// defaults to "token" if unset.
+	// +optional
+	AuthSecretRef *corev1.SecretKeySelector `json:"authSecretRef,omitempty"`
+
+	// ToolPrefix, if set, is prepended to every tool name this proxy
+	// advertises, so upstreams that happen to expose a same-named tool
+	// don't collide.
+	// +optional
+	ToolPrefix string `json:"toolPrefix,omitempty"`
+
+	// Enabled controls whether this proxy is connected. Defaults to true;
+	// set to false to disconnect it without deleting the object.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// MCPProxyPhase summarizes the current state of an MCPProxy's upstream
+// connection.
+type MCPProxyPhase string
+
+const (
+	MCPProxyPhaseConnected    MCPProxyPhase = "Connected"
+	MCPProxyPhaseDisconnected MCPProxyPhase = "Disconnected"
+	MCPProxyPhaseDisabled     MCPProxyPhase = "Disabled"
+)
+
+// MCPProxyStatus reports the observed state of an MCPProxy.
+type MCPProxyStatus struct {
+	// Phase summarizes the current connection state.
+	// +optional
+	Phase MCPProxyPhase `json:"phase,omitempty"`
+
+	// Message gives more detail when Phase is Disconnected, typically the
+	// last connection error.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Tools lists the tool names most recently advertised from this
+	// upstream, after ToolPrefix is applied.
+	// +optional
+	Tools []string `json:"tools,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Transport",type=string,JSONPath=`.spec.transport`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+
+// MCPProxy declares an upstream MCP server whose tools the kubegram
+// operator should proxy, so new tool sources (Prometheus, Vault,
+// cloud-provider MCPs, ...) can be onboarded declaratively instead of by
+// editing operator flags. See MCPProxyReconciler in pkg/mcp.
+type MCPProxy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MCPProxySpec   `json:"spec,omitempty"`
+	Status MCPProxyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MCPProxyList contains a list of MCPProxy.
+type MCPProxyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MCPProxy `json:"items"`
+}
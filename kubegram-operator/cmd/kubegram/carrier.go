@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"os"
+
+	"github.com/gorilla/websocket"
+	"github.com/kubegram/kubegram-operator/pkg/carrier"
+)
+
+// runCarrier implements the "carrier" subcommand: it registers with an
+// operator's carrier endpoint under --name, then relays bytes between the
+// resulting tunnel and either a local TCP port or this process's own
+// stdin/stdout, until the tunnel closes.
+func runCarrier(args []string) error {
+	fs := flag.NewFlagSet("carrier", flag.ExitOnError)
+	operatorAddr := fs.String("operator-addr", "", "host:port of the operator's carrier endpoint (e.g. localhost:8083)")
+	name := fs.String("name", "", "Name to register this carrier under; shown in the synthetic port-forward-<id> tool's description")
+	localPort := fs.Int("local-port", 0, "Local TCP port to relay bytes to/from")
+	useStdio := fs.Bool("stdio", false, "Relay bytes to/from this process's stdin/stdout instead of a local TCP port")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *operatorAddr == "" || *name == "" {
+		return fmt.Errorf("--operator-addr and --name are required")
+	}
+	if (*localPort != 0) == *useStdio {
+		return fmt.Errorf("exactly one of --local-port or --stdio is required")
+	}
+
+	u := url.URL{Scheme: "ws", Host: *operatorAddr, Path: "/carrier"}
+	ws, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to operator at %s: %w", *operatorAddr, err)
+	}
+
+	reg, err := json.Marshal(struct {
+		Name string `json:"name"`
+	}{Name: *name})
+	if err != nil {
+		return fmt.Errorf("failed to marshal registration: %w", err)
+	}
+	if err := ws.WriteMessage(websocket.TextMessage, reg); err != nil {
+		ws.Close()
+		return fmt.Errorf("failed to register carrier: %w", err)
+	}
+
+	var local io.ReadWriteCloser
+	if *useStdio {
+		local = stdioConn{}
+	} else {
+		conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", *localPort))
+		if err != nil {
+			ws.Close()
+			return fmt.Errorf("failed to dial local port %d: %w", *localPort, err)
+		}
+		local = conn
+	}
+	defer local.Close()
+
+	tunnel := carrier.NewConn(*name, ws)
+	defer tunnel.Close()
+
+	log.Printf("carrier %q registered with %s, relaying...", *name, *operatorAddr)
+	return relay(tunnel, local)
+}
+
+// relay copies bytes in both directions between tunnel and local until
+// either side closes or errors.
+func relay(tunnel *carrier.Conn, local io.ReadWriteCloser) error {
+	errCh := make(chan error, 2)
+	go func() {
+		_, err := io.Copy(tunnel, local)
+		errCh <- err
+	}()
+	go func() {
+		_, err := io.Copy(local, tunnel)
+		errCh <- err
+	}()
+	return <-errCh
+}
+
+// stdioConn adapts os.Stdin/os.Stdout to an io.ReadWriteCloser for --stdio
+// mode; Close is a no-op since closing the process's real stdio streams
+// isn't meaningful here.
+type stdioConn struct{}
+
+func (stdioConn) Read(p []byte) (int, error)  { return os.Stdin.Read(p) }
+func (stdioConn) Write(p []byte) (int, error) { return os.Stdout.Write(p) }
+func (stdioConn) Close() error                { return nil }
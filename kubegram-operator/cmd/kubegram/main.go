@@ -0,0 +1,40 @@
+// Command kubegram is a small local CLI companion to the kubegram-operator
+// MCP server. Its "carrier" subcommand lets a user expose a local TCP port
+// or their own stdio pair as a synthetic "port-forward-<id>" MCP tool on a
+// running operator, so an LLM talking to that operator can drive it like
+// any other tool (see pkg/carrier).
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "carrier":
+		err = runCarrier(os.Args[2:])
+	case "-h", "--help", "help":
+		usage()
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "kubegram: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "kubegram: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: kubegram carrier --operator-addr <host:port> --name <name> (--local-port <port> | --stdio)")
+}
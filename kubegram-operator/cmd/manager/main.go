@@ -2,18 +2,27 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"flag"
 	"fmt"
 	"net/http"
+	"net/http/pprof"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
+	kubegramv1alpha1 "github.com/kubegram/kubegram-operator/api/v1alpha1"
+	"github.com/kubegram/kubegram-operator/pkg/carrier"
+	"github.com/kubegram/kubegram-operator/pkg/kube/contextmgr"
 	"github.com/kubegram/kubegram-operator/pkg/mcp"
-	"github.com/kubegram/kubegram-operator/pkg/transport"
+	mcpauth "github.com/kubegram/kubegram-operator/pkg/mcp/auth"
+	"github.com/kubegram/kubegram-operator/pkg/tools"
+	"github.com/kubegram/kubegram-operator/pkg/tools/policy"
 	sdkMcp "github.com/modelcontextprotocol/go-sdk/mcp"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -28,6 +37,7 @@ var (
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(kubegramv1alpha1.AddToScheme(scheme))
 }
 
 func main() {
@@ -56,11 +66,58 @@ func main() {
 	flag.StringVar(&k8sMCPCmd, "k8s-mcp-cmd", "", "Command to run Kubernetes MCP server (e.g. 'uvx kubernetes-mcp-server')")
 	flag.StringVar(&k8sMCPURL, "k8s-mcp-url", "", "URL to Kubernetes MCP server (e.g. 'http://k8s-mcp:8080/sse')")
 
+	// Multi-cluster support: these let an MCP client target a specific
+	// kubeconfig context instead of always using the ambient config.
+	var argoMCPContext string
+	var k8sMCPContext string
+	var extraKubeconfigs string
+	flag.StringVar(&argoMCPContext, "argo-mcp-context", "", "Kubeconfig context to pass to the Argo MCP command (--argo-mcp-cmd only)")
+	flag.StringVar(&k8sMCPContext, "k8s-mcp-context", "", "Kubeconfig context to pass to the Kubernetes MCP command (--k8s-mcp-cmd only)")
+	flag.StringVar(&extraKubeconfigs, "extra-kubeconfigs", "", "Comma-separated additional kubeconfig paths the native k8s tools should load contexts from")
+
+	// Policy flags: guardrails around the bash/kubectl tools and audit
+	// logging for every tool call. See pkg/tools/policy.
+	var policyAllow string
+	var policyDeny string
+	var policyReadOnly bool
+	var policyTimeoutSecs int
+	var policyMaxOutputBytes int
+	flag.StringVar(&policyAllow, "policy-allow", "", "Comma-separated regexes; if non-empty, only matching bash/kubectl commands are permitted")
+	flag.StringVar(&policyDeny, "policy-deny", "", "Comma-separated regexes; matching bash/kubectl commands are always rejected")
+	flag.BoolVar(&policyReadOnly, "policy-read-only", false, "Reject mutating kubectl verbs and the equivalent native k8s_apply/k8s_delete/k8s_scale/k8s_exec tools regardless of --policy-allow; bash commands are unaffected unless --policy-allow is also set")
+	flag.IntVar(&policyTimeoutSecs, "policy-timeout-seconds", 0, "Per-call timeout for local tool invocations; 0 disables the extra timeout")
+	flag.IntVar(&policyMaxOutputBytes, "policy-max-output-bytes", 0, "Truncate tool call output beyond this many bytes; 0 disables truncation")
+
 	// Flag for MCP HTTP Server
 	// By default, the MCP server runs on Stdio (standard input/output) for integration with local clients like Claude Desktop.
 	// Setting this flag enables HTTP/SSE mode, which is useful for remote connections or debugging.
 	flag.StringVar(&mcpHTTPAddr, "mcp-http-addr", "", "Address to bind MCP HTTP server (e.g. ':8082'). If empty, runs on Stdio.")
 
+	// Flags for authenticating/authorizing MCP HTTP/SSE callers against the
+	// Kubernetes API server. See pkg/mcp/auth. Auth is independent of TLS:
+	// it's built whenever --mcp-http-addr runs, since a caller reaching it
+	// is just as often behind a TLS-terminating ingress/LB (no cert on the
+	// operator itself) as presenting TLS straight to it.
+	var mcpTLSCert string
+	var mcpTLSKey string
+	var mcpClientCA string
+	var mcpInsecureNoAuth bool
+	flag.StringVar(&mcpTLSCert, "mcp-tls-cert", "", "Path to a TLS certificate for the MCP HTTP server; requires --mcp-tls-key. If empty, the MCP HTTP server is plaintext (authn/authz still applies unless --mcp-insecure-no-auth is set).")
+	flag.StringVar(&mcpTLSKey, "mcp-tls-key", "", "Path to the TLS private key matching --mcp-tls-cert")
+	flag.StringVar(&mcpClientCA, "mcp-client-ca", "", "Path to a CA bundle for verifying client certificates presented to the MCP HTTP server; callers may still authenticate with a bearer token instead")
+	flag.BoolVar(&mcpInsecureNoAuth, "mcp-insecure-no-auth", false, "Run the MCP HTTP/SSE server (--mcp-http-addr) without TokenReview/SubjectAccessReview authn+authz. Dangerous: only for local debugging on a trusted network.")
+
+	var carrierAddr string
+	flag.StringVar(&carrierAddr, "carrier-addr", "", "Address to bind the kubegram carrier registration endpoint (e.g. ':8083'), serving /carrier. If empty, carrier support is disabled.")
+
+	// Debug endpoint: pprof/expvar/proxy-status, gated by the same
+	// TokenReview/SAR auth as the MCP HTTP server (following the k3s change
+	// that moved pprof behind client-cert auth on the supervisor listener)
+	// so it's safe to leave enabled on a production cluster. Disabled unless
+	// --debug-bind-address is set.
+	var debugBindAddr string
+	flag.StringVar(&debugBindAddr, "debug-bind-address", "", "Address to bind an authenticated pprof/expvar/proxy-status endpoint to (e.g. ':8084'), serving /debug/pprof, /debug/vars and /debug/mcp/proxies. If empty, the debug endpoint is disabled.")
+
 	var llmWebSocketURL string
 	flag.StringVar(&llmWebSocketURL, "llm-websocket-url", "", "URL for external LLM WebSocket service")
 
@@ -115,78 +172,288 @@ func main() {
 		k8sCmd = strings.Fields(k8sMCPCmd)
 	}
 
+	var kubeconfigs []string
+	if extraKubeconfigs != "" {
+		kubeconfigs = strings.Split(extraKubeconfigs, ",")
+	}
+
+	if mcpTLSCert != "" && mcpTLSKey == "" {
+		setupLog.Error(fmt.Errorf("--mcp-tls-key is required"), "invalid MCP TLS flags")
+		os.Exit(1)
+	}
+
+	// mcpAuthorizer requires every MCP tool call to pass a SubjectAccessReview
+	// for the caller identity Middleware resolves from the HTTP request;
+	// mcpAuthenticator resolves that identity. Built whenever the MCP HTTP
+	// server (--mcp-http-addr) runs, regardless of whether --mcp-tls-cert is
+	// set, unless --mcp-insecure-no-auth opts out. Both stay nil when
+	// --mcp-http-addr is unset, since the Stdio/WebSocket MCP servers below
+	// never see an http.Request for Middleware to authenticate.
+	var mcpAuthenticator *mcpauth.Authenticator
+	var mcpAuthorizer *mcpauth.Authorizer
+	if mcpHTTPAddr != "" && !mcpInsecureNoAuth {
+		clientset, err := kubernetes.NewForConfig(ctrl.GetConfigOrDie())
+		if err != nil {
+			setupLog.Error(err, "unable to build Kubernetes clientset for MCP auth")
+			os.Exit(1)
+		}
+		mcpAuthenticator = &mcpauth.Authenticator{Client: clientset}
+		mcpAuthorizer = &mcpauth.Authorizer{Client: clientset}
+	}
+
+	// debugAuthenticator/debugAuthorizer gate --debug-bind-address the same
+	// way mcpAuthenticator/mcpAuthorizer gate the MCP HTTP server; reuse
+	// those if already built rather than opening a second clientset.
+	debugAuthenticator, debugAuthorizer := mcpAuthenticator, mcpAuthorizer
+	if debugBindAddr != "" && debugAuthenticator == nil {
+		clientset, err := kubernetes.NewForConfig(ctrl.GetConfigOrDie())
+		if err != nil {
+			setupLog.Error(err, "unable to build Kubernetes clientset for debug auth")
+			os.Exit(1)
+		}
+		debugAuthenticator = &mcpauth.Authenticator{Client: clientset}
+		debugAuthorizer = &mcpauth.Authorizer{Client: clientset}
+	}
+
+	var toolPolicy *policy.Policy
+	if policyAllow != "" || policyDeny != "" || policyReadOnly || policyTimeoutSecs > 0 || policyMaxOutputBytes > 0 {
+		var allow, deny []string
+		if policyAllow != "" {
+			allow = strings.Split(policyAllow, ",")
+		}
+		if policyDeny != "" {
+			deny = strings.Split(policyDeny, ",")
+		}
+		p, err := policy.New(policy.Policy{
+			Allow:          allow,
+			Deny:           deny,
+			ReadOnly:       policyReadOnly,
+			Timeout:        time.Duration(policyTimeoutSecs) * time.Second,
+			MaxOutputBytes: policyMaxOutputBytes,
+		})
+		if err != nil {
+			setupLog.Error(err, "invalid tool policy flags")
+			os.Exit(1)
+		}
+		toolPolicy = p
+	}
+
 	ctx := context.Background()
-	proxies := mcp.InitProxies(ctx, argoCmd, argoMCPURL, k8sCmd, k8sMCPURL)
+	pfManager := contextmgr.NewManager(kubeconfigs)
+	pfRegistry := tools.NewPortForwardRegistry()
+	proxies := mcp.InitProxies(ctx, argoCmd, argoMCPURL, argoMCPContext, k8sCmd, k8sMCPURL, k8sMCPContext, pfManager, pfRegistry, nil)
+	proxyManager := mcp.NewProxyManager(proxies)
+
+	// liveServer pairs a tracked MCP server with the authorizer (if any) it
+	// was actually constructed with, so a later re-registration (proxy
+	// reconnect, MCPProxy connect, carrier register) applies the same
+	// authorization behavior the server started with instead of
+	// unconditionally assuming mcpAuthorizer applies — it only does on the
+	// HTTP/SSE transport, where mcpauth.Middleware runs.
+	type liveServer struct {
+		server     *sdkMcp.Server
+		authorizer *mcpauth.Authorizer
+	}
+
+	// liveServers tracks every MCP server instance created below, so that
+	// when proxyManager notices a proxy reconnect with a refreshed tool set
+	// we can re-register it everywhere, not just on whichever server
+	// happened to be running at the time.
+	var liveServersMu sync.Mutex
+	var liveServers []liveServer
+	trackServer := func(s *sdkMcp.Server, a *mcpauth.Authorizer) {
+		liveServersMu.Lock()
+		liveServers = append(liveServers, liveServer{server: s, authorizer: a})
+		liveServersMu.Unlock()
+	}
+
+	proxyManager.Start(ctx, mcp.DefaultProxyPingInterval, func(name string, refreshedTools []*sdkMcp.Tool) {
+		setupLog.Info("MCP proxy reconnected, re-advertising its tools", "proxy", name, "tools", len(refreshedTools))
+		var proxy *mcp.ProxyClient
+		for _, p := range proxyManager.Proxies() {
+			if p.Name == name {
+				proxy = p
+				break
+			}
+		}
+		if proxy == nil {
+			return
+		}
+		liveServersMu.Lock()
+		defer liveServersMu.Unlock()
+		for _, ls := range liveServers {
+			mcp.RegisterProxyTools(ls.server, toolPolicy, ls.authorizer, proxy)
+		}
+	})
+
+	// carrierRegistry is passed to every mcp.NewServer call below (via
+	// mcp.WithCarrierRegistry) so a server created after a carrier is
+	// already registered still advertises its tool; onCarrierRegister below
+	// additionally pushes the tool onto every already-live server
+	// immediately, the same way proxy reconnects do above.
+	carrierRegistry := carrier.NewRegistry()
+	carrierManager := carrier.NewManager()
+
+	onCarrierRegister := func(name string, conn *carrier.Conn) {
+		id := carrierRegistry.Register(name, conn)
+		setupLog.Info("Carrier registered", "name", name, "id", id)
+
+		liveServersMu.Lock()
+		for _, ls := range liveServers {
+			carrierManager.AddTool(ls.server, id, name, conn, ls.authorizer)
+		}
+		liveServersMu.Unlock()
 
-	// Start WebSocket MCP Server
-	if llmWebSocketURL != "" {
 		go func() {
-			setupLog.Info("Starting WebSocket MCP Server")
-			// Retry loop for WebSocket connection
-			for {
-				select {
-				case <-ctx.Done():
-					return
-				default:
-				}
-
-				server := mcp.NewServer(ctx, proxies)
-				// Re-use transport package import
-				wsTransport := transport.NewWebSocketTransport(llmWebSocketURL)
-
-				setupLog.Info("Connecting to WebSocket MCP", "url", llmWebSocketURL)
-				if err := server.Run(ctx, wsTransport); err != nil {
-					setupLog.Error(err, "WebSocket MCP server disconnected, retrying in 5s")
-					select {
-					case <-ctx.Done():
-						return
-					case <-time.After(5 * time.Second):
-						continue
-					}
-				}
-				// If Run returns nil, it might mean the transport closed gracefully or context ended
-				setupLog.Info("WebSocket MCP server stopped")
-				select {
-				case <-ctx.Done():
-					return
-				case <-time.After(1 * time.Second):
-					// Small backoff to prevent tight loop if it returns immediately without error
-					continue
-				}
+			conn.Wait()
+			setupLog.Info("Carrier disconnected", "name", name, "id", id)
+			carrierRegistry.Unregister(id)
+			liveServersMu.Lock()
+			for _, ls := range liveServers {
+				carrierManager.RemoveTool(ls.server, id)
 			}
+			liveServersMu.Unlock()
 		}()
 	}
 
-	// Start Stdio/HTTP MCP Server
-	// We run this if specific flags are set or if we want default Stdio behavior
-	// mirroring the previous logic: if argo/k8s cmds are present OR http addr is set.
-	if argoMCPCmd != "" || k8sMCPCmd != "" || mcpHTTPAddr != "" {
+	// dynamicProxyRegistry is passed to every mcp.NewServer call below (via
+	// mcp.WithDynamicProxyRegistry) so a server created after an MCPProxy
+	// object already exists still advertises its tools; mcpProxyReconciler's
+	// OnConnect/OnDisconnect additionally push tool add/remove onto every
+	// already-live server immediately, the same way onCarrierRegister does
+	// for carriers.
+	dynamicProxyRegistry := mcp.NewDynamicProxyRegistry()
+	mcpProxyReconciler := &mcp.MCPProxyReconciler{
+		Client:       mgr.GetClient(),
+		Registry:     dynamicProxyRegistry,
+		SuperviseCtx: ctx,
+		OnConnect: func(key string, proxy *mcp.ProxyClient) {
+			liveServersMu.Lock()
+			defer liveServersMu.Unlock()
+			for _, ls := range liveServers {
+				mcp.RegisterProxyTools(ls.server, toolPolicy, ls.authorizer, proxy)
+			}
+		},
+		OnDisconnect: func(key string, proxy *mcp.ProxyClient) {
+			liveServersMu.Lock()
+			defer liveServersMu.Unlock()
+			for _, ls := range liveServers {
+				mcp.UnregisterProxyTools(ls.server, proxy)
+			}
+		},
+	}
+	if err := mcpProxyReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MCPProxy")
+		os.Exit(1)
+	}
+
+	// Carrier support: a local kubegram CLI can register a TCP port or
+	// stdio pair it exposes, which becomes a "port-forward-<id>" tool on
+	// every live MCP server (see pkg/carrier). Disabled unless --carrier-addr
+	// is set.
+	if carrierAddr != "" {
 		go func() {
-			setupLog.Info("Starting Standard MCP Server")
-			// Note: StartMCPServer creates its own proxies, which is duplicative/wasteful if we already created them.
-			// So we invoke NewServer directly.
-			server := mcp.NewServer(ctx, proxies)
-
-			if mcpHTTPAddr != "" {
-				setupLog.Info("Starting HTTP MCP Server", "addr", mcpHTTPAddr)
-
-				sseHandler := sdkMcp.NewSSEHandler(func(r *http.Request) *sdkMcp.Server {
-					return server
-				}, nil)
-				if err := http.ListenAndServe(mcpHTTPAddr, sseHandler); err != nil {
-					setupLog.Error(err, "HTTP MCP server failed")
-				}
-			} else {
-				setupLog.Info("Starting Stdio MCP Server")
-				// Stdio
-				t := &sdkMcp.StdioTransport{}
-				if err := server.Run(ctx, t); err != nil {
-					setupLog.Error(err, "Stdio MCP server failed")
-				}
+			setupLog.Info("Starting carrier registration endpoint", "addr", carrierAddr)
+			mux := http.NewServeMux()
+			mux.Handle("/carrier", carrier.Handler(onCarrierRegister))
+			if err := http.ListenAndServe(carrierAddr, mux); err != nil {
+				setupLog.Error(err, "carrier registration endpoint failed")
 			}
 		}()
 	}
 
+	// Debug endpoint: net/http/pprof, expvar and a proxy-status dump, gated
+	// by the same TokenReview/SAR middleware as the MCP HTTP server so it's
+	// safe to enable in production. Disabled unless --debug-bind-address is
+	// set.
+	if debugBindAddr != "" {
+		go func() {
+			setupLog.Info("Starting debug endpoint", "addr", debugBindAddr)
+			mux := http.NewServeMux()
+			mux.HandleFunc("/debug/pprof/", pprof.Index)
+			mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+			mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+			mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+			mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+			mux.Handle("/debug/vars", expvar.Handler())
+			mux.Handle("/debug/mcp/proxies", mcp.ProxyDebugHandler(proxyManager, dynamicProxyRegistry))
+
+			handler := mcpauth.Middleware(debugAuthenticator, debugAuthorizer, mux)
+			if err := http.ListenAndServe(debugBindAddr, handler); err != nil {
+				setupLog.Error(err, "debug endpoint failed")
+			}
+		}()
+	}
+
+	// newServer builds an MCP server sharing this process's proxies and
+	// registries, applying extraOpts (e.g. mcp.WithAuthorizer) on top of the
+	// options every server gets. Each Runnable below gets its own newServer
+	// closure so that mgr restarting a Runnable (e.g. after a leadership
+	// handoff) rebuilds a fresh server rather than reusing one tied to a
+	// cancelled ctx.
+	newServer := func(extraOpts ...mcp.NewServerOption) func(ctx context.Context) *sdkMcp.Server {
+		opts := append([]mcp.NewServerOption{mcp.WithExtraKubeconfigs(kubeconfigs...), mcp.WithPolicy(toolPolicy), mcp.WithPortForwardRegistry(pfRegistry), mcp.WithCarrierRegistry(carrierRegistry), mcp.WithDynamicProxyRegistry(dynamicProxyRegistry)}, extraOpts...)
+		return func(ctx context.Context) *sdkMcp.Server {
+			return mcp.NewServer(ctx, proxies, opts...)
+		}
+	}
+
+	// Register the WebSocket, Stdio and HTTP MCP servers as manager.Runnables
+	// instead of bare goroutines, so they start/stop with the manager's own
+	// lifecycle (leader-election gating, context cancellation on shutdown)
+	// and surface a /readyz sub-check and their terminal error back to mgr.
+	if llmWebSocketURL != "" {
+		wsRunnable := &websocketRunnable{
+			url:         llmWebSocketURL,
+			newServer:   newServer(),
+			trackServer: trackServer,
+		}
+		if err := mgr.Add(wsRunnable); err != nil {
+			setupLog.Error(err, "unable to register WebSocket MCP server")
+			os.Exit(1)
+		}
+		if err := mgr.AddReadyzCheck("mcp-websocket", wsRunnable.Check); err != nil {
+			setupLog.Error(err, "unable to set up ready check", "check", "mcp-websocket")
+			os.Exit(1)
+		}
+	}
+
+	// mirrors the previous logic: if argo/k8s cmds are present OR http addr
+	// is set, run Stdio (the default) or HTTP/SSE depending on mcpHTTPAddr.
+	if mcpHTTPAddr != "" {
+		httpR := &httpRunnable{
+			addr:          mcpHTTPAddr,
+			tlsCert:       mcpTLSCert,
+			tlsKey:        mcpTLSKey,
+			clientCA:      mcpClientCA,
+			authenticator: mcpAuthenticator,
+			authorizer:    mcpAuthorizer,
+			newServer:     newServer(mcp.WithAuthorizer(mcpAuthorizer)),
+			trackServer:   trackServer,
+		}
+		if err := mgr.Add(httpR); err != nil {
+			setupLog.Error(err, "unable to register HTTP MCP server")
+			os.Exit(1)
+		}
+		if err := mgr.AddReadyzCheck("mcp-http", httpR.Check); err != nil {
+			setupLog.Error(err, "unable to set up ready check", "check", "mcp-http")
+			os.Exit(1)
+		}
+	} else if argoMCPCmd != "" || k8sMCPCmd != "" {
+		stdioR := &stdioRunnable{
+			newServer:   newServer(),
+			trackServer: trackServer,
+		}
+		if err := mgr.Add(stdioR); err != nil {
+			setupLog.Error(err, "unable to register Stdio MCP server")
+			os.Exit(1)
+		}
+		if err := mgr.AddReadyzCheck("mcp-stdio", stdioR.Check); err != nil {
+			setupLog.Error(err, "unable to set up ready check", "check", "mcp-stdio")
+			os.Exit(1)
+		}
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	mcpauth "github.com/kubegram/kubegram-operator/pkg/mcp/auth"
+	mcpmetrics "github.com/kubegram/kubegram-operator/pkg/mcp/metrics"
+	"github.com/kubegram/kubegram-operator/pkg/transport"
+	sdkMcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// httpShutdownTimeout bounds how long httpRunnable waits for in-flight
+// tool calls and SSE connections to drain once its Start ctx is done,
+// before giving up and returning whatever http.Server.Shutdown reports.
+const httpShutdownTimeout = 30 * time.Second
+
+// readiness is a mutex-guarded readiness flag a Runnable flips once it's
+// actually serving (or stops serving), backing its mgr.AddReadyzCheck
+// sub-check. Zero value reports not ready, matching a Runnable that
+// hasn't been started by mgr yet.
+type readiness struct {
+	mu    sync.Mutex
+	ready bool
+	err   error
+}
+
+func (r *readiness) set(ready bool, err error) {
+	r.mu.Lock()
+	r.ready, r.err = ready, err
+	r.mu.Unlock()
+}
+
+// Check implements healthz.Checker.
+func (r *readiness) Check(req *http.Request) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ready {
+		return nil
+	}
+	if r.err != nil {
+		return r.err
+	}
+	return fmt.Errorf("not yet ready")
+}
+
+// websocketRunnable dials llmWebSocketURL and serves MCP tool calls over
+// it as a manager.Runnable, redialing after a fixed delay on disconnect (5s
+// after an error, 1s after a clean close) the same way the original retry
+// loop did. It requires leadership (see
+// NeedLeaderElection): its tool calls perform mutating actions against
+// Argo and Kubernetes on the remote LLM's behalf, so running it on more
+// than one replica at a time would let two replicas dispatch the same
+// call twice.
+type websocketRunnable struct {
+	readiness
+	url         string
+	newServer   func(ctx context.Context) *sdkMcp.Server
+	trackServer func(s *sdkMcp.Server, a *mcpauth.Authorizer)
+}
+
+func (r *websocketRunnable) NeedLeaderElection() bool { return true }
+
+// Start dials r.url, retries on disconnect, and returns nil once ctx is
+// done — mgr treats a Runnable that returns promptly on ctx.Done as having
+// stopped cleanly. Each attempt's server.Run blocks for the life of the
+// session, so in-flight tool calls finish (or ctx's cancellation propagates
+// down to whatever they're waiting on) before Start retries or returns.
+func (r *websocketRunnable) Start(ctx context.Context) error {
+	setupLog.Info("Starting WebSocket MCP Server")
+	first := true
+	for {
+		select {
+		case <-ctx.Done():
+			r.set(false, nil)
+			return nil
+		default:
+		}
+
+		if !first {
+			mcpmetrics.WebSocketReconnectsTotal.Inc()
+		}
+		first = false
+
+		// No WithAuthorizer here: mcpauth.Identity is only ever placed on
+		// the call context by mcpauth.Middleware, which wraps httpRunnable's
+		// handler, not this transport.
+		server := r.newServer(ctx)
+		r.trackServer(server, nil)
+		wsTransport := transport.NewWebSocketTransport(r.url)
+
+		setupLog.Info("Connecting to WebSocket MCP", "url", r.url)
+		mcpmetrics.ActiveSessions.WithLabelValues("websocket").Inc()
+		r.set(true, nil)
+		err := server.Run(ctx, wsTransport)
+		mcpmetrics.ActiveSessions.WithLabelValues("websocket").Dec()
+		r.set(false, err)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			setupLog.Error(err, "WebSocket MCP server disconnected, retrying in 5s")
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(5 * time.Second):
+				continue
+			}
+		}
+		// If Run returns nil, the transport closed gracefully; back off
+		// briefly so a server that returns immediately without error
+		// doesn't spin the loop.
+		setupLog.Info("WebSocket MCP server stopped")
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(1 * time.Second):
+			continue
+		}
+	}
+}
+
+// stdioRunnable serves MCP tool calls over Stdio as a manager.Runnable. It
+// requires leadership for the same reason websocketRunnable does: its
+// tool calls mutate Argo/Kubernetes state, so only one replica should
+// dispatch them. (In practice Stdio mode — a single long-lived client
+// process talking to the operator's own stdin/stdout — only makes sense
+// with a single replica anyway; the leader-election gate keeps that true
+// even if it's scaled up by mistake.)
+type stdioRunnable struct {
+	readiness
+	newServer   func(ctx context.Context) *sdkMcp.Server
+	trackServer func(s *sdkMcp.Server, a *mcpauth.Authorizer)
+}
+
+func (r *stdioRunnable) NeedLeaderElection() bool { return true }
+
+func (r *stdioRunnable) Start(ctx context.Context) error {
+	setupLog.Info("Starting Stdio MCP Server")
+	server := r.newServer(ctx)
+	r.trackServer(server, nil)
+
+	t := &sdkMcp.StdioTransport{}
+	mcpmetrics.ActiveSessions.WithLabelValues("stdio").Inc()
+	r.set(true, nil)
+	err := server.Run(ctx, t)
+	mcpmetrics.ActiveSessions.WithLabelValues("stdio").Dec()
+	r.set(false, err)
+	if err != nil {
+		setupLog.Error(err, "Stdio MCP server failed")
+	}
+	return err
+}
+
+// httpRunnable serves MCP tool calls over HTTP/SSE as a manager.Runnable.
+// Unlike websocketRunnable/stdioRunnable it does not require leadership
+// (see NeedLeaderElection): every registered tool call is authorized
+// per-caller via mcpauth (when mcpauth.Middleware is wired in, i.e.
+// authenticator is non-nil), so it's safe to run on every replica behind
+// a Service, scaling read-heavy tool traffic horizontally instead of
+// bottlenecking it on whichever replica holds leadership.
+type httpRunnable struct {
+	readiness
+	addr          string
+	tlsCert       string
+	tlsKey        string
+	clientCA      string
+	authenticator *mcpauth.Authenticator
+	authorizer    *mcpauth.Authorizer
+	newServer     func(ctx context.Context) *sdkMcp.Server
+	trackServer   func(s *sdkMcp.Server, a *mcpauth.Authorizer)
+}
+
+func (r *httpRunnable) NeedLeaderElection() bool { return false }
+
+// Start serves HTTP/SSE until ctx is done, then drains in-flight tool
+// calls and SSE connections via http.Server.Shutdown (bounded by
+// httpShutdownTimeout) before returning, so mgr.Start's shutdown doesn't
+// yank a connection out from under an in-progress call.
+func (r *httpRunnable) Start(ctx context.Context) error {
+	server := r.newServer(ctx)
+	r.trackServer(server, r.authorizer)
+
+	var handler http.Handler = sdkMcp.NewSSEHandler(func(req *http.Request) *sdkMcp.Server {
+		return server
+	}, nil)
+	if r.authenticator != nil {
+		handler = mcpauth.Middleware(r.authenticator, r.authorizer, handler)
+	}
+	handler = mcpmetrics.TrackSessions("http", handler)
+
+	httpServer := &http.Server{Addr: r.addr, Handler: handler}
+	if r.tlsCert != "" {
+		tlsConfig, err := mcpauth.TLSConfig(r.clientCA)
+		if err != nil {
+			return fmt.Errorf("invalid --mcp-client-ca: %w", err)
+		}
+		httpServer.TLSConfig = tlsConfig
+	}
+
+	serveErrCh := make(chan error, 1)
+	go func() {
+		setupLog.Info("Starting HTTP MCP Server", "addr", r.addr)
+		var err error
+		if r.tlsCert != "" {
+			err = httpServer.ListenAndServeTLS(r.tlsCert, r.tlsKey)
+		} else {
+			err = httpServer.ListenAndServe()
+		}
+		if err == http.ErrServerClosed {
+			err = nil
+		}
+		serveErrCh <- err
+	}()
+	r.set(true, nil)
+
+	select {
+	case err := <-serveErrCh:
+		r.set(false, err)
+		return err
+	case <-ctx.Done():
+		r.set(false, nil)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+		return <-serveErrCh
+	}
+}
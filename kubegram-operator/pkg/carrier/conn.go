@@ -0,0 +1,216 @@
+// Package carrier lets a local kubegram CLI expose a local TCP port or its
+// own stdio pair as a synthetic MCP tool on the operator (see cmd/kubegram's
+// "carrier" subcommand and Manager.AddTool). The operator calls the tool like
+// any other; behind the scenes its CallTool handler pipes bytes to and from
+// the CLI over a websocket, turning the MCP server into a general
+// port-forward/exec plane without inventing a new wire protocol.
+package carrier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// methodData frames carry a chunk of tunneled bytes.
+	methodData = "carrier/data"
+	// methodClose tells the peer no more data is coming and the connection
+	// is being torn down.
+	methodClose = "carrier/close"
+)
+
+// frame is the JSON-RPC-notification-shaped message carrier sends over a
+// text websocket frame. Params.Data round-trips as base64 via
+// encoding/json's standard []byte handling, so arbitrary binary payloads
+// survive intact without any manual encoding/base64 calls. A Conn also
+// accepts raw binary websocket frames as data chunks directly, since both
+// ends of carrier always have one available; frame exists so the protocol
+// degrades to plain JSON-RPC notifications if that ever isn't true.
+type frame struct {
+	Method string `json:"method"`
+	Params struct {
+		Data []byte `json:"data,omitempty"`
+	} `json:"params,omitempty"`
+}
+
+// Conn is one end of a bidirectional byte tunnel carried over a websocket.
+// It implements io.ReadWriteCloser: Write sends a chunk as a binary
+// websocket frame, Read pulls whole chunks off the wire, and ReadAvailable
+// offers the same thing bounded by a context instead of blocking forever.
+// Both the operator side (Handler) and the CLI side (cmd/kubegram) build one
+// around their end of the same websocket, since the wire protocol is
+// symmetric.
+type Conn struct {
+	// Name is the identifier the CLI registered this carrier under (see
+	// registerRequest), used to label the synthetic tool Manager.AddTool
+	// creates for it.
+	Name string
+
+	ws *websocket.Conn
+
+	mu       sync.Mutex
+	leftover []byte
+
+	incoming chan []byte
+	errCh    chan error
+
+	closeOnce sync.Once
+	closeCh   chan struct{}
+	doneOnce  sync.Once
+	doneCh    chan struct{}
+}
+
+// NewConn wraps ws as a Conn named name and starts its read loop.
+func NewConn(name string, ws *websocket.Conn) *Conn {
+	c := &Conn{
+		Name:     name,
+		ws:       ws,
+		incoming: make(chan []byte, 16),
+		errCh:    make(chan error, 1),
+		closeCh:  make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+	go c.readLoop()
+	return c
+}
+
+// readLoop drains ws, delivering binary frames and methodData frame payloads
+// to incoming, until ws errors or the peer sends methodClose.
+func (c *Conn) readLoop() {
+	defer close(c.incoming)
+	defer c.markDone()
+
+	for {
+		msgType, data, err := c.ws.ReadMessage()
+		if err != nil {
+			select {
+			case c.errCh <- err:
+			default:
+			}
+			return
+		}
+
+		switch msgType {
+		case websocket.BinaryMessage:
+			c.deliver(data)
+		case websocket.TextMessage:
+			var f frame
+			if json.Unmarshal(data, &f) != nil {
+				continue
+			}
+			switch f.Method {
+			case methodClose:
+				return
+			case methodData:
+				c.deliver(f.Params.Data)
+			}
+		}
+	}
+}
+
+func (c *Conn) deliver(data []byte) {
+	select {
+	case c.incoming <- data:
+	case <-c.closeCh:
+	}
+}
+
+func (c *Conn) markDone() {
+	c.doneOnce.Do(func() { close(c.doneCh) })
+}
+
+// Read implements io.Reader, filling p from whatever chunks have arrived. A
+// chunk larger than p is buffered and drained across subsequent Reads.
+func (c *Conn) Read(p []byte) (int, error) {
+	c.mu.Lock()
+	if len(c.leftover) > 0 {
+		n := copy(p, c.leftover)
+		c.leftover = c.leftover[n:]
+		c.mu.Unlock()
+		return n, nil
+	}
+	c.mu.Unlock()
+
+	select {
+	case data, ok := <-c.incoming:
+		if !ok {
+			return 0, io.EOF
+		}
+		n := copy(p, data)
+		if n < len(data) {
+			c.mu.Lock()
+			c.leftover = append(c.leftover, data[n:]...)
+			c.mu.Unlock()
+		}
+		return n, nil
+	case err := <-c.errCh:
+		return 0, err
+	case <-c.closeCh:
+		return 0, io.EOF
+	}
+}
+
+// ReadAvailable blocks for the next chunk the peer sends (or returns
+// buffered leftover from a prior Read), returning it whole as soon as it
+// arrives rather than waiting to fill a buffer. It returns io.EOF once the
+// connection closes, or ctx's error if its deadline elapses first. This is
+// what a carrier tool's CallTool handler uses: a tool call wants "whatever
+// came back within the timeout", not an exact byte count.
+func (c *Conn) ReadAvailable(ctx context.Context) ([]byte, error) {
+	c.mu.Lock()
+	if len(c.leftover) > 0 {
+		data := c.leftover
+		c.leftover = nil
+		c.mu.Unlock()
+		return data, nil
+	}
+	c.mu.Unlock()
+
+	select {
+	case data, ok := <-c.incoming:
+		if !ok {
+			return nil, io.EOF
+		}
+		return data, nil
+	case err := <-c.errCh:
+		return nil, err
+	case <-c.closeCh:
+		return nil, io.EOF
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Write sends p as a single binary websocket frame.
+func (c *Conn) Write(p []byte) (int, error) {
+	if err := c.ws.WriteMessage(websocket.BinaryMessage, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close tells the peer no more data is coming and closes the underlying
+// websocket. It is safe to call more than once.
+func (c *Conn) Close() error {
+	c.closeOnce.Do(func() {
+		var f frame
+		f.Method = methodClose
+		if data, err := json.Marshal(f); err == nil {
+			_ = c.ws.WriteMessage(websocket.TextMessage, data)
+		}
+		close(c.closeCh)
+	})
+	return c.ws.Close()
+}
+
+// Wait blocks until the connection's read loop exits, whether because Close
+// was called, the peer sent methodClose, or the websocket errored. The
+// manager's carrier registration callback waits on it before calling
+// Manager.RemoveTool for the carrier's synthetic tool.
+func (c *Conn) Wait() {
+	<-c.doneCh
+}
@@ -0,0 +1,50 @@
+package carrier
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+// registerRequest is the first message a connecting kubegram CLI sends after
+// the websocket upgrade: the name its carrier (and the synthetic tool built
+// around it) should be registered under.
+type registerRequest struct {
+	Name string `json:"name"`
+}
+
+var upgrader = websocket.Upgrader{
+	// The other end is the kubegram CLI a cluster operator runs locally
+	// against their own operator instance, not an arbitrary browser client,
+	// so the default same-origin check (aimed at browsers) doesn't apply
+	// here — mirrors the trust model of the plain MCP SSE/WS endpoints.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// Handler upgrades incoming requests to a websocket, reads the CLI's
+// registerRequest and hands the resulting Conn to onRegister (see
+// Manager.AddTool) to be turned into a synthetic MCP tool.
+func Handler(onRegister func(name string, conn *Conn)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to upgrade to websocket: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		_, data, err := ws.ReadMessage()
+		if err != nil {
+			ws.Close()
+			return
+		}
+		var req registerRequest
+		if err := json.Unmarshal(data, &req); err != nil || req.Name == "" {
+			ws.Close()
+			return
+		}
+
+		onRegister(req.Name, NewConn(req.Name, ws))
+	})
+}
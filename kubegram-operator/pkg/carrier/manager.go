@@ -0,0 +1,120 @@
+package carrier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	mcpauth "github.com/kubegram/kubegram-operator/pkg/mcp/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// DefaultReadTimeout bounds how long a carrier tool call waits for a
+// response from the CLI once callInput.Data has been written (or
+// immediately, if it was empty), when the call doesn't set TimeoutSeconds.
+const DefaultReadTimeout = 30 * time.Second
+
+func mustGenerateSchema(t reflect.Type) *jsonschema.Schema {
+	schema, err := jsonschema.ForType(t, &jsonschema.ForOptions{})
+	if err != nil {
+		panic(fmt.Sprintf("carrier: failed to generate schema for %s: %v", t, err))
+	}
+	return schema
+}
+
+// callInput is the input schema for a synthetic "port-forward-<id>" tool.
+type callInput struct {
+	// Data, if set, is written into the tunnel before a response is read.
+	// It round-trips as base64 via encoding/json's []byte handling.
+	Data []byte `json:"data,omitempty"`
+	// TimeoutSeconds bounds how long to wait for a response after Data is
+	// written. 0 uses DefaultReadTimeout.
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+}
+
+// callOutput is the result of a carrier tool call: whatever bytes the CLI
+// sent back, base64-encoded the same way as callInput.Data.
+type callOutput struct {
+	Data []byte `json:"data"`
+}
+
+// Manager builds and tears down the synthetic "port-forward-<id>" tool that
+// backs one carrier Conn. Its CallTool handler writes its caller's input
+// into the Conn, then reads whatever the CLI streams back within a timeout —
+// turning an arbitrary TCP stream or stdio pair the CLI exposes into a
+// callable MCP tool, the same way PortForwardTool turns a cluster-side
+// port-forward into one. Unlike a Registry, a Manager holds no state of its
+// own; a caller tracking several live *mcp.Server instances for the same
+// Conn (see RegisterProxyTools for the analogous proxy-tool case) calls
+// AddTool/RemoveTool once per server.
+type Manager struct{}
+
+// NewManager creates a Manager.
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// ToolName returns the synthetic tool name a carrier registered under id is
+// exposed as.
+func ToolName(id string) string {
+	return fmt.Sprintf("port-forward-%s", id)
+}
+
+// AddTool registers the "port-forward-<id>" tool for conn (registered in a
+// Registry as name) on server. authz, if non-nil, requires a
+// SubjectAccessReview to pass before every call, the same way server.go's
+// wrapTool does for the operator's other tools.
+func (m *Manager) AddTool(server *mcp.Server, id, name string, conn *Conn, authz *mcpauth.Authorizer) {
+	toolName := ToolName(id)
+	tool := mcp.Tool{
+		Name:        toolName,
+		Description: fmt.Sprintf("Tunnel bytes to/from the kubegram carrier CLI registered as %q: writes data (base64), if any, into the carrier, then returns whatever it streams back within timeout_seconds.", name),
+		InputSchema: mustGenerateSchema(reflect.TypeOf(callInput{})),
+	}
+	server.AddTool(&tool, mcpauth.WrapTool(authz, toolName, m.handler(id, conn)))
+}
+
+// RemoveTool removes the "port-forward-<id>" tool from server.
+func (m *Manager) RemoveTool(server *mcp.Server, id string) {
+	server.RemoveTools(ToolName(id))
+}
+
+// handler builds the CallTool handler for the carrier registered under id.
+func (m *Manager) handler(id string, conn *Conn) func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var in callInput
+		if len(request.Params.Arguments) > 0 {
+			if err := json.Unmarshal(request.Params.Arguments, &in); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal arguments: %w", err)
+			}
+		}
+
+		if len(in.Data) > 0 {
+			if _, err := conn.Write(in.Data); err != nil {
+				return nil, fmt.Errorf("failed to write to carrier %s: %w", id, err)
+			}
+		}
+
+		timeout := DefaultReadTimeout
+		if in.TimeoutSeconds > 0 {
+			timeout = time.Duration(in.TimeoutSeconds) * time.Second
+		}
+		readCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		data, err := conn.ReadAvailable(readCtx)
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read from carrier %s: %w", id, err)
+		}
+
+		out, err := json.Marshal(callOutput{Data: data})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal result: %w", err)
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(out)}}}, nil
+	}
+}
@@ -0,0 +1,103 @@
+package carrier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestToolName(t *testing.T) {
+	if got, want := ToolName("carrier-1"), "port-forward-carrier-1"; got != want {
+		t.Errorf("ToolName(%q) = %q, want %q", "carrier-1", got, want)
+	}
+}
+
+// newConnPair dials an httptest websocket server and wraps each end in a
+// *Conn, so handler tests exercise the real read loop instead of a fake.
+func newConnPair(t *testing.T) (client, server *Conn) {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	srvReady := make(chan *websocket.Conn, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("server upgrade: %v", err)
+			return
+		}
+		srvReady <- ws
+	}))
+	t.Cleanup(ts.Close)
+
+	url := "ws" + ts.URL[len("http"):]
+	clientWS, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	serverWS := <-srvReady
+
+	client = NewConn("client", clientWS)
+	server = NewConn("server", serverWS)
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	return client, server
+}
+
+func TestManager_HandlerWritesInputAndReturnsResponse(t *testing.T) {
+	client, server := newConnPair(t)
+
+	// Act as the CLI: echo back whatever arrives, uppercased-by-nothing
+	// (the handler under test only cares that a response round-trips).
+	go func() {
+		data, err := client.ReadAvailable(context.Background())
+		if err != nil {
+			return
+		}
+		client.Write(append([]byte("echo:"), data...))
+	}()
+
+	m := NewManager()
+	handler := m.handler("carrier-1", server)
+
+	args, _ := json.Marshal(callInput{Data: []byte("hi"), TimeoutSeconds: 2})
+	result, err := handler(context.Background(), &mcp.CallToolRequest{
+		Params: &mcp.CallToolParamsRaw{Arguments: args},
+	})
+	if err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	var out callOutput
+	if err := json.Unmarshal([]byte(text), &out); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if string(out.Data) != "echo:hi" {
+		t.Errorf("got data %q, want %q", out.Data, "echo:hi")
+	}
+}
+
+func TestManager_HandlerTimesOutWithoutAResponse(t *testing.T) {
+	_, server := newConnPair(t)
+
+	m := NewManager()
+	handler := m.handler("carrier-1", server)
+
+	args, _ := json.Marshal(callInput{TimeoutSeconds: 1})
+	start := time.Now()
+	if _, err := handler(context.Background(), &mcp.CallToolRequest{
+		Params: &mcp.CallToolParamsRaw{Arguments: args},
+	}); err == nil {
+		t.Error("expected a timeout error when nothing writes a response")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("handler took %s, want close to the 1s TimeoutSeconds", elapsed)
+	}
+}
@@ -0,0 +1,66 @@
+package carrier
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Entry is one registered carrier, as returned by List.
+type Entry struct {
+	ID   string
+	Name string
+	Conn *Conn
+}
+
+// Registry tracks every carrier Conn currently attached to a server, mirroring
+// tools.PortForwardRegistry's shape: each Conn is assigned a short id that
+// becomes part of its synthetic "port-forward-<id>" tool name.
+type Registry struct {
+	mu       sync.Mutex
+	carriers map[string]*Conn
+	nextID   int
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{carriers: map[string]*Conn{}}
+}
+
+// Register adds conn under a freshly assigned id and returns it.
+func (r *Registry) Register(name string, conn *Conn) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	id := fmt.Sprintf("carrier-%d", r.nextID)
+	r.carriers[id] = conn
+	return id
+}
+
+// Get returns the carrier registered under id, if any.
+func (r *Registry) Get(id string) (*Conn, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	conn, ok := r.carriers[id]
+	return conn, ok
+}
+
+// Unregister removes the carrier registered under id, if any.
+func (r *Registry) Unregister(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.carriers, id)
+}
+
+// List returns every currently registered carrier, so a newly created
+// *mcp.Server can advertise tools for carriers that registered before it
+// existed (see mcp.WithCarrierRegistry).
+func (r *Registry) List() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]Entry, 0, len(r.carriers))
+	for id, conn := range r.carriers {
+		entries = append(entries, Entry{ID: id, Name: conn.Name, Conn: conn})
+	}
+	return entries
+}
@@ -0,0 +1,63 @@
+package carrier
+
+import "testing"
+
+func TestRegistry_RegisterGetUnregister(t *testing.T) {
+	r := NewRegistry()
+	conn := &Conn{Name: "my-carrier"}
+
+	id := r.Register("my-carrier", conn)
+	if id == "" {
+		t.Fatal("expected a non-empty id")
+	}
+
+	got, ok := r.Get(id)
+	if !ok || got != conn {
+		t.Fatalf("Get(%q) = %v, %v; want %v, true", id, got, ok, conn)
+	}
+
+	r.Unregister(id)
+	if _, ok := r.Get(id); ok {
+		t.Errorf("expected carrier %q to be gone after Unregister", id)
+	}
+}
+
+func TestRegistry_RegisterAssignsDistinctIDs(t *testing.T) {
+	r := NewRegistry()
+	id1 := r.Register("a", &Conn{Name: "a"})
+	id2 := r.Register("b", &Conn{Name: "b"})
+	if id1 == id2 {
+		t.Errorf("expected distinct ids, got %q twice", id1)
+	}
+}
+
+func TestRegistry_ListReturnsEveryRegisteredCarrier(t *testing.T) {
+	r := NewRegistry()
+	connA := &Conn{Name: "a"}
+	connB := &Conn{Name: "b"}
+	idA := r.Register("a", connA)
+	idB := r.Register("b", connB)
+
+	entries := r.List()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+
+	byID := map[string]Entry{}
+	for _, e := range entries {
+		byID[e.ID] = e
+	}
+	if byID[idA].Name != "a" || byID[idA].Conn != connA {
+		t.Errorf("entry for %q = %+v, want Name=a Conn=%v", idA, byID[idA], connA)
+	}
+	if byID[idB].Name != "b" || byID[idB].Conn != connB {
+		t.Errorf("entry for %q = %+v, want Name=b Conn=%v", idB, byID[idB], connB)
+	}
+}
+
+func TestRegistry_GetUnknownID(t *testing.T) {
+	r := NewRegistry()
+	if _, ok := r.Get("nope"); ok {
+		t.Error("expected Get of an unregistered id to return false")
+	}
+}
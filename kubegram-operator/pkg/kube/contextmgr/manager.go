@@ -0,0 +1,141 @@
+// Package contextmgr resolves named kubeconfig contexts to cached Kubernetes
+// clients, so MCP tools can target a specific cluster instead of always
+// using the ambient kubeconfig/in-cluster config.
+package contextmgr
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Client bundles the typed and dynamic clients, RESTMapper and REST config
+// for a single resolved context.
+type Client struct {
+	Typed   kubernetes.Interface
+	Dynamic dynamic.Interface
+	Mapper  meta.RESTMapper
+	Config  *rest.Config
+}
+
+// ResourceFor resolves a GroupVersionKind to the GroupVersionResource (and
+// whether it is namespaced) needed to address it through the dynamic client.
+func (c *Client) ResourceFor(gvk schema.GroupVersionKind) (schema.GroupVersionResource, bool, error) {
+	mapping, err := c.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, fmt.Errorf("failed to map %s: %w", gvk.String(), err)
+	}
+	return mapping.Resource, mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+}
+
+// Manager loads every context from one or more kubeconfigs and lazily builds
+// and caches a Client per context name.
+type Manager struct {
+	mu     sync.Mutex
+	config clientcmd.ClientConfig
+	cache  map[string]*Client
+}
+
+// NewManager creates a Manager from the default kubeconfig loading rules,
+// plus any additional kubeconfig paths supplied (e.g. via ServerOptions).
+func NewManager(extraKubeconfigs []string) *Manager {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if len(extraKubeconfigs) > 0 {
+		loadingRules.Precedence = append(loadingRules.Precedence, extraKubeconfigs...)
+	}
+	config := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+	return &Manager{config: config, cache: map[string]*Client{}}
+}
+
+// Contexts returns the names of every context known to the manager.
+func (m *Manager) Contexts() ([]string, error) {
+	raw, err := m.config.RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	names := make([]string, 0, len(raw.Contexts))
+	for name := range raw.Contexts {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// CurrentContext returns the name of the context that would be used when no
+// explicit context is requested.
+func (m *Manager) CurrentContext() (string, error) {
+	raw, err := m.config.RawConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	return raw.CurrentContext, nil
+}
+
+// Resolve returns the cached Client for ctxName, building and caching it on
+// first use. An empty ctxName resolves to an in-cluster config when running
+// inside a Pod, falling back to the kubeconfig's current context otherwise.
+func (m *Manager) Resolve(ctxName string) (*Client, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if cached, ok := m.cache[ctxName]; ok {
+		return cached, nil
+	}
+
+	config, err := m.restConfig(ctxName)
+	if err != nil {
+		return nil, err
+	}
+
+	typed, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create typed client for context %q: %w", ctxName, err)
+	}
+
+	dyn, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client for context %q: %w", ctxName, err)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client for context %q: %w", ctxName, err)
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+
+	client := &Client{Typed: typed, Dynamic: dyn, Mapper: mapper, Config: config}
+	m.cache[ctxName] = client
+	return client, nil
+}
+
+// restConfig builds a *rest.Config for ctxName. An empty ctxName prefers the
+// in-cluster config, matching the single-cluster behavior tools had before
+// context support existed.
+func (m *Manager) restConfig(ctxName string) (*rest.Config, error) {
+	if ctxName == "" {
+		if config, err := rest.InClusterConfig(); err == nil {
+			return config, nil
+		}
+		return m.config.ClientConfig()
+	}
+
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: ctxName}
+	raw, err := m.config.RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	if _, ok := raw.Contexts[ctxName]; !ok {
+		return nil, fmt.Errorf("unknown context %q", ctxName)
+	}
+
+	namedConfig := clientcmd.NewDefaultClientConfig(raw, overrides)
+	return namedConfig.ClientConfig()
+}
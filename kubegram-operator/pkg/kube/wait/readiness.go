@@ -0,0 +1,119 @@
+package wait
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubegram/kubegram-operator/pkg/kube/contextmgr"
+)
+
+// isReady inspects a single fetched object and reports whether it meets the
+// readiness bar for its kind. Kinds with no readiness rule here (anything
+// not in the switch) are treated as ready as soon as they exist, matching
+// Helm's behavior for resource kinds it does not specially wait on.
+func isReady(ctx context.Context, client *contextmgr.Client, obj *unstructured.Unstructured) (bool, string, error) {
+	switch obj.GetKind() {
+	case "Deployment", "StatefulSet", "DaemonSet":
+		return rolloutReady(obj)
+	case "Pod":
+		ready := conditionTrue(obj, "Ready")
+		if !ready {
+			return false, "pod is not Ready", nil
+		}
+		return true, "pod is Ready", nil
+	case "Job":
+		return jobReady(obj)
+	case "PersistentVolumeClaim":
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		if phase != "Bound" {
+			return false, fmt.Sprintf("pvc phase is %q, want Bound", phase), nil
+		}
+		return true, "pvc is Bound", nil
+	case "CustomResourceDefinition":
+		if !conditionTrue(obj, "Established") {
+			return false, "crd is not Established", nil
+		}
+		return true, "crd is Established", nil
+	case "Service":
+		return serviceReady(ctx, client, obj)
+	default:
+		return true, "no readiness check defined for this kind, treating as ready", nil
+	}
+}
+
+// rolloutReady implements the Deployment/StatefulSet/DaemonSet readiness
+// check shared across the three workload kinds: the controller must have
+// observed the latest generation, and its updated/available replica counts
+// must match what was requested.
+func rolloutReady(obj *unstructured.Unstructured) (bool, string, error) {
+	generation, _, _ := unstructured.NestedInt64(obj.Object, "metadata", "generation")
+	observedGeneration, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if observedGeneration < generation {
+		return false, fmt.Sprintf("observedGeneration %d has not caught up to generation %d", observedGeneration, generation), nil
+	}
+
+	var desired int64 = 1
+	if replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); found {
+		desired = replicas
+	}
+	updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedReplicas")
+	available, _, _ := unstructured.NestedInt64(obj.Object, "status", "availableReplicas")
+
+	if updated < desired {
+		return false, fmt.Sprintf("%d of %d replicas updated", updated, desired), nil
+	}
+	if available < desired {
+		return false, fmt.Sprintf("%d of %d replicas available", available, desired), nil
+	}
+	return true, fmt.Sprintf("%d/%d replicas available", available, desired), nil
+}
+
+// jobReady reports Ready once the Job has a "Complete" condition, and treats
+// a "Failed" condition as a terminal (non-retryable) not-ready state.
+func jobReady(obj *unstructured.Unstructured) (bool, string, error) {
+	if conditionTrue(obj, "Failed") {
+		return false, "job has Failed condition", fmt.Errorf("job %s failed", obj.GetName())
+	}
+	if conditionTrue(obj, "Complete") {
+		return true, "job is Complete", nil
+	}
+	return false, "job has not completed", nil
+}
+
+// serviceReady requires a ClusterIP/LoadBalancer Service to have at least
+// one populated address in its Endpoints, i.e. at least one backing Pod is
+// ready to receive traffic.
+func serviceReady(ctx context.Context, client *contextmgr.Client, obj *unstructured.Unstructured) (bool, string, error) {
+	endpoints, err := client.Typed.CoreV1().Endpoints(obj.GetNamespace()).Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Sprintf("failed to fetch endpoints: %v", err), nil
+	}
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) > 0 {
+			return true, "service has ready endpoints", nil
+		}
+	}
+	return false, "service has no ready endpoints yet", nil
+}
+
+// conditionTrue reports whether obj's status.conditions contains an entry of
+// the given type with status "True".
+func conditionTrue(obj *unstructured.Unstructured, condType string) bool {
+	conditions, found, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if !found {
+		return false
+	}
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if cond["type"] == condType {
+			return cond["status"] == "True"
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+// Package wait polls the Kubernetes API until a set of objects reach a
+// ready state, mirroring the subset of Helm's kube.Waiter logic needed by
+// tools that do not go through a Helm release (e.g. k8s_apply/k8s_wait).
+package wait
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/kubegram/kubegram-operator/pkg/kube/contextmgr"
+)
+
+// pollInterval is how often WaitForReady re-checks objects that are not yet ready.
+const pollInterval = 2 * time.Second
+
+// Status reports whether a single object reached readiness, and why not if it didn't.
+type Status struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Ready     bool   `json:"ready"`
+	Message   string `json:"message,omitempty"`
+}
+
+// WaitForReady polls each of objects, on client, until it is ready or
+// timeout elapses. It always waits out the full set (it does not abort the
+// rest on one object's failure) so callers get a complete picture of what is
+// and isn't ready. The returned error is non-nil only for unexpected errors
+// resolving a GVK to a resource; a plain timeout is reported via Status.Ready
+// == false, not an error.
+func WaitForReady(ctx context.Context, client *contextmgr.Client, objects []*unstructured.Unstructured, timeout time.Duration) ([]Status, error) {
+	deadline := time.Now().Add(timeout)
+	statuses := make([]Status, len(objects))
+
+	for i, obj := range objects {
+		status := Status{Kind: obj.GetKind(), Name: obj.GetName(), Namespace: obj.GetNamespace()}
+
+		gvr, namespaced, err := client.ResourceFor(obj.GroupVersionKind())
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s/%s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+
+		objCtx, cancel := context.WithDeadline(ctx, deadline)
+		ready, msg := pollOne(objCtx, client, gvr, obj.GetNamespace(), obj.GetName(), namespaced, obj.GetKind())
+		cancel()
+
+		status.Ready = ready
+		status.Message = msg
+		statuses[i] = status
+	}
+
+	return statuses, nil
+}
+
+// WaitForNamedCondition polls a single object until its status.conditions
+// contains an entry of the given type with status "True", or timeout
+// elapses. Use this instead of WaitForReady when the caller wants to wait on
+// an arbitrary named condition rather than the kind's built-in readiness rule.
+func WaitForNamedCondition(ctx context.Context, client *contextmgr.Client, obj *unstructured.Unstructured, conditionType string, timeout time.Duration) (bool, string, error) {
+	gvr, namespaced, err := client.ResourceFor(obj.GroupVersionKind())
+	if err != nil {
+		return false, "", fmt.Errorf("failed to resolve %s/%s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+
+	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(timeout))
+	defer cancel()
+
+	var lastMsg string
+	err = wait.PollUntilContextCancel(ctx, pollInterval, true, func(ctx context.Context) (bool, error) {
+		var (
+			current *unstructured.Unstructured
+			getErr  error
+		)
+		if namespaced {
+			current, getErr = client.Dynamic.Resource(gvr).Namespace(obj.GetNamespace()).Get(ctx, obj.GetName(), metav1.GetOptions{})
+		} else {
+			current, getErr = client.Dynamic.Resource(gvr).Get(ctx, obj.GetName(), metav1.GetOptions{})
+		}
+		if getErr != nil {
+			lastMsg = getErr.Error()
+			return false, nil
+		}
+		if conditionTrue(current, conditionType) {
+			lastMsg = fmt.Sprintf("condition %s is True", conditionType)
+			return true, nil
+		}
+		lastMsg = fmt.Sprintf("condition %s is not True", conditionType)
+		return false, nil
+	})
+	if err != nil {
+		return false, lastMsg, nil
+	}
+	return true, lastMsg, nil
+}
+
+func pollOne(ctx context.Context, client *contextmgr.Client, gvr schema.GroupVersionResource, namespace, name string, namespaced bool, kind string) (bool, string) {
+	var lastMsg string
+
+	err := wait.PollUntilContextCancel(ctx, pollInterval, true, func(ctx context.Context) (bool, error) {
+		var (
+			current *unstructured.Unstructured
+			getErr  error
+		)
+		if namespaced {
+			current, getErr = client.Dynamic.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		} else {
+			current, getErr = client.Dynamic.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+		}
+		if getErr != nil {
+			lastMsg = getErr.Error()
+			return false, nil
+		}
+
+		ready, msg, endpointsErr := isReady(ctx, client, current)
+		if endpointsErr != nil {
+			lastMsg = endpointsErr.Error()
+			return false, endpointsErr
+		}
+		lastMsg = msg
+		return ready, nil
+	})
+	if err != nil {
+		if lastMsg == "" {
+			lastMsg = fmt.Sprintf("timed out waiting for %s/%s to become ready", kind, name)
+		}
+		return false, lastMsg
+	}
+	return true, lastMsg
+}
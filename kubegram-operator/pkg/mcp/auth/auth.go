@@ -0,0 +1,118 @@
+// Package auth authenticates and authorizes callers of the kubegram
+// operator's MCP HTTP/SSE server against the Kubernetes API server,
+// borrowing the TokenReview/SubjectAccessReview pattern k3s uses for its
+// supervisor listener: every caller is resolved to a Kubernetes user via a
+// bearer token or client certificate (Authenticator), then every tool call
+// is checked against that user's RBAC permissions on a synthetic
+// kubegram.io/mcp-tools resource (Authorizer).
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ErrUnauthenticated is returned by Authenticator.Authenticate when a
+// request carries no usable credential, or the Kubernetes API server
+// rejects the one it does carry. Callers should respond 401 either way.
+var ErrUnauthenticated = fmt.Errorf("no valid bearer token or client certificate presented")
+
+// Identity is the Kubernetes user a caller's bearer token or client
+// certificate resolved to.
+type Identity struct {
+	Username string
+	UID      string
+	Groups   []string
+	Extra    map[string]authorizationv1.ExtraValue
+}
+
+type identityContextKey struct{}
+
+// WithIdentity returns a copy of ctx carrying identity, so a later
+// per-tool-call authorization check (see WrapTool) can see who's calling
+// without re-authenticating. Middleware calls this for every request it
+// admits.
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityContextKey{}, identity)
+}
+
+// IdentityFromContext returns the Identity stashed by WithIdentity, if any.
+func IdentityFromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityContextKey{}).(*Identity)
+	return identity, ok
+}
+
+// Authenticator resolves an incoming HTTP request to a Kubernetes Identity:
+// first via a bearer token, checked against the API server's TokenReview
+// endpoint, then — if no bearer token is present — via a verified client
+// certificate, mapped to a username/groups the same way the API server's
+// x509 authenticator does.
+type Authenticator struct {
+	Client kubernetes.Interface
+}
+
+// Authenticate resolves r's caller. It returns ErrUnauthenticated if r
+// carries no usable credential or the TokenReview rejects its bearer token,
+// and a "token review failed" error wrapping the API error if the
+// TokenReview request itself fails.
+func (a *Authenticator) Authenticate(ctx context.Context, r *http.Request) (*Identity, error) {
+	if token, ok := bearerToken(r); ok {
+		return a.authenticateToken(ctx, token)
+	}
+	if identity, ok := certIdentity(r); ok {
+		return identity, nil
+	}
+	return nil, ErrUnauthenticated
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	const prefix = "Bearer "
+	value := r.Header.Get("Authorization")
+	if !strings.HasPrefix(value, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(value, prefix), true
+}
+
+func (a *Authenticator) authenticateToken(ctx context.Context, token string) (*Identity, error) {
+	review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+	result, err := a.Client.AuthenticationV1().TokenReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("token review failed: %w", err)
+	}
+	if !result.Status.Authenticated {
+		return nil, ErrUnauthenticated
+	}
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(result.Status.User.Extra))
+	for k, v := range result.Status.User.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	return &Identity{
+		Username: result.Status.User.Username,
+		UID:      result.Status.User.UID,
+		Groups:   result.Status.User.Groups,
+		Extra:    extra,
+	}, nil
+}
+
+// certIdentity maps a verified client certificate's Subject the way the
+// Kubernetes API server's x509 authenticator does: CommonName is the
+// username, Organization entries are groups. r.TLS.PeerCertificates is only
+// populated once a verified chain exists, which requires the server to be
+// configured with a ClientCAs pool (see TLSConfig) and a client to have
+// presented a cert signed by it.
+func certIdentity(r *http.Request) (*Identity, bool) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return nil, false
+	}
+	cert := r.TLS.PeerCertificates[0]
+	return &Identity{Username: cert.Subject.CommonName, Groups: cert.Subject.Organization}, true
+}
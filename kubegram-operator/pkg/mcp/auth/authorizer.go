@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ResourceGroup and Resource name the synthetic resource Authorize checks
+// RBAC rules against, e.g.:
+//
+//	rules:
+//	- apiGroups: ["kubegram.io"]
+//	  resources: ["mcp-tools"]
+//	  verbs: ["get"]
+//	  resourceNames: ["k8s_delete"]
+const (
+	ResourceGroup = "kubegram.io"
+	Resource      = "mcp-tools"
+)
+
+// Authorizer checks an Identity's Kubernetes RBAC permissions via
+// SubjectAccessReview against the synthetic kubegram.io/mcp-tools resource,
+// one resourceName per MCP tool (see ToolVerb).
+type Authorizer struct {
+	Client kubernetes.Interface
+}
+
+// Authorize runs a SubjectAccessReview for verb against the mcp-tools
+// resource named resourceName (empty for Middleware's connection-level
+// baseline check), returning nil if allowed or an error embedding the SAR's
+// denial reason otherwise.
+func (a *Authorizer) Authorize(ctx context.Context, identity *Identity, verb, resourceName string) error {
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   identity.Username,
+			UID:    identity.UID,
+			Groups: identity.Groups,
+			Extra:  identity.Extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Group:    ResourceGroup,
+				Resource: Resource,
+				Verb:     verb,
+				Name:     resourceName,
+			},
+		},
+	}
+	result, err := a.Client.AuthorizationV1().SubjectAccessReviews().Create(ctx, sar, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("subject access review failed: %w", err)
+	}
+	if !result.Status.Allowed {
+		reason := result.Status.Reason
+		if reason == "" {
+			reason = fmt.Sprintf("%s is not permitted to %s %s/%s %q", identity.Username, verb, ResourceGroup, Resource, resourceName)
+		}
+		return fmt.Errorf("forbidden: %s", reason)
+	}
+	return nil
+}
+
+// toolVerbs maps the operator's built-in tool names to the RBAC verb an
+// Authorize call should check for them, grouping read-only tools under
+// "get" and everything that can create, change or remove cluster or
+// external state under a more specific mutating verb.
+var toolVerbs = map[string]string{
+	"list_contexts":      "get",
+	"current_context":    "get",
+	"k8s_get":            "get",
+	"k8s_list":           "get",
+	"k8s_logs":           "get",
+	"k8s_wait":           "get",
+	"k8s_apply":          "update",
+	"k8s_delete":         "delete",
+	"k8s_scale":          "update",
+	"k8s_exec":           "update",
+	"helm_list":          "get",
+	"helm_install":       "create",
+	"helm_upgrade":       "update",
+	"helm_uninstall":     "delete",
+	"helm_repo_add":      "update",
+	"install_argo_mcp":   "create",
+	"bash":               "update",
+	"kubectl":            "update",
+	"port_forward":       "create",
+	"list_port_forwards": "get",
+	"stop_port_forward":  "delete",
+}
+
+// ToolVerb maps toolName to the RBAC verb Authorize should check it
+// against. Tools this table doesn't know about — proxied tools (whose
+// names come from an upstream MCP server) and carrier tools — default to
+// "update", the conservative choice for a handler that might mutate
+// cluster or external state.
+func ToolVerb(toolName string) string {
+	if verb, ok := toolVerbs[toolName]; ok {
+		return verb
+	}
+	return "update"
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// Middleware wraps next (e.g. the SDK's SSE handler) with Kubernetes
+// authn/authz: a caller without a valid bearer token or client certificate
+// gets 401; an authenticated caller who fails a baseline
+// SubjectAccessReview (verb "get" on the unnamed kubegram.io/mcp-tools
+// resource — "can this user reach the MCP server at all") gets 403 with
+// the SAR's reason. A caller that passes both has its Identity stashed on
+// the request context (see WithIdentity) so WrapTool's per-tool-call
+// SubjectAccessReview, further downstream, doesn't need to re-authenticate.
+func Middleware(authn *Authenticator, authz *Authorizer, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, err := authn.Authenticate(r.Context(), r)
+		if err != nil {
+			http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if err := authz.Authorize(r.Context(), identity, "get", ""); err != nil {
+			http.Error(w, "Forbidden: "+err.Error(), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), identity)))
+	})
+}
+
+// TLSConfig builds the tls.Config a TLS-serving MCP HTTP listener needs.
+// When clientCAFile is set, a presented client certificate is verified
+// against it (but not required — a caller can still authenticate with a
+// bearer token instead), so Authenticator.Authenticate can fall back to
+// cert-based identity.
+func TLSConfig(clientCAFile string) (*tls.Config, error) {
+	if clientCAFile == "" {
+		return &tls.Config{}, nil
+	}
+
+	pemBytes, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file %s: %w", clientCAFile, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemBytes) {
+		return nil, fmt.Errorf("no certificates found in client CA file %s", clientCAFile)
+	}
+
+	return &tls.Config{
+		ClientCAs:  pool,
+		ClientAuth: tls.VerifyClientCertIfGiven,
+	}, nil
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// WrapTool runs a per-call SubjectAccessReview (verb ToolVerb(toolName) on
+// the mcp-tools resource named toolName) before invoking next, using the
+// Identity Middleware stashed on the request context. Denied or
+// unauthenticated calls return a tool-level error result (IsError: true)
+// rather than an RPC error, the same way policy.Policy's Check* failures
+// do, so a client sees why its call was rejected instead of a generic
+// failure. authz == nil returns next unwrapped, matching this package's
+// other optional-by-default behavior.
+func WrapTool(authz *Authorizer, toolName string, next func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error)) func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if authz == nil {
+		return next
+	}
+	return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		identity, ok := IdentityFromContext(ctx)
+		if !ok {
+			return denyResult(ErrUnauthenticated), nil
+		}
+		if err := authz.Authorize(ctx, identity, ToolVerb(toolName), toolName); err != nil {
+			return denyResult(err), nil
+		}
+		return next(ctx, request)
+	}
+}
+
+func denyResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}},
+	}
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func alwaysAllow(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return &mcp.CallToolResult{}, nil
+}
+
+func TestWrapTool_DeniesWithoutIdentity(t *testing.T) {
+	authz := &Authorizer{Client: fake.NewSimpleClientset()}
+	wrapped := WrapTool(authz, "k8s_delete", alwaysAllow)
+
+	result, err := wrapped(context.Background(), &mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("WrapTool returned an RPC error, want a tool-level IsError result: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError result for a call with no Identity in context")
+	}
+}
+
+func TestWrapTool_DeniesOnFailedSubjectAccessReview(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		sar := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		sar.Status = authorizationv1.SubjectAccessReviewStatus{Allowed: false, Reason: "no rbac rule"}
+		return true, sar, nil
+	})
+	authz := &Authorizer{Client: client}
+	wrapped := WrapTool(authz, "k8s_delete", alwaysAllow)
+
+	ctx := WithIdentity(context.Background(), &Identity{Username: "alice"})
+	result, err := wrapped(ctx, &mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("WrapTool returned an RPC error, want a tool-level IsError result: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError result for a denied SubjectAccessReview")
+	}
+}
+
+func TestWrapTool_NilAuthorizerPassesThrough(t *testing.T) {
+	wrapped := WrapTool(nil, "k8s_delete", alwaysAllow)
+
+	ctx := context.Background()
+	result, err := wrapped(ctx, &mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected authz == nil to run next unwrapped")
+	}
+}
@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// proxyStatus is one upstream's entry in ProxyDebugHandler's dump.
+type proxyStatus struct {
+	Name      string `json:"name"`
+	Connected bool   `json:"connected"`
+	LastError string `json:"last_error,omitempty"`
+	Tools     int    `json:"tools"`
+}
+
+// ProxyDebugHandler dumps every upstream's connection status, last error
+// (if any) and discovered tool count as JSON, covering both the
+// flag-configured proxies pm tracks and any MCPProxy-object-managed ones
+// dynamicRegistry tracks. Mounted at /debug/mcp/proxies, behind the same
+// auth.Middleware gating the rest of --debug-bind-address.
+func ProxyDebugHandler(pm *ProxyManager, dynamicRegistry *DynamicProxyRegistry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var statuses []proxyStatus
+		for _, p := range pm.Proxies() {
+			statuses = append(statuses, proxyStatusOf(p))
+		}
+		for _, entry := range dynamicRegistry.List() {
+			statuses = append(statuses, proxyStatusOf(entry.Client))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		data, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(data)
+	})
+}
+
+func proxyStatusOf(p *ProxyClient) proxyStatus {
+	status := proxyStatus{Name: p.Name, Tools: len(p.Tools())}
+	if err := p.LastError(); err != nil {
+		status.LastError = err.Error()
+	} else {
+		status.Connected = true
+	}
+	return status
+}
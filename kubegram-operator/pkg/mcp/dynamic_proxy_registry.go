@@ -0,0 +1,61 @@
+package mcp
+
+import "sync"
+
+// DynamicProxyEntry is one upstream MCPProxy object's live proxy client, as
+// returned by DynamicProxyRegistry.List.
+type DynamicProxyEntry struct {
+	// Key is the proxy's "<namespace>/<name>", matching ProxyClient.Name.
+	Key    string
+	Client *ProxyClient
+}
+
+// DynamicProxyRegistry tracks the ProxyClients MCPProxyReconciler has
+// brought up from live MCPProxy objects, keyed by "<namespace>/<name>",
+// mirroring carrier.Registry's shape: NewServer (via
+// WithDynamicProxyRegistry) consults List so a server created after an
+// MCPProxy object already exists still advertises its tools; the
+// reconciler additionally pushes tool add/remove onto every already-live
+// server directly as objects are created, updated, disabled or deleted,
+// via its OnConnect/OnDisconnect callbacks (see cmd/manager/main.go's
+// onCarrierRegister for the analogous carrier case).
+type DynamicProxyRegistry struct {
+	mu      sync.Mutex
+	proxies map[string]*ProxyClient
+}
+
+// NewDynamicProxyRegistry creates an empty registry.
+func NewDynamicProxyRegistry() *DynamicProxyRegistry {
+	return &DynamicProxyRegistry{proxies: map[string]*ProxyClient{}}
+}
+
+// Set records proxy as the current live connection for key, replacing
+// whatever was there before.
+func (r *DynamicProxyRegistry) Set(key string, proxy *ProxyClient) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.proxies[key] = proxy
+}
+
+// Delete removes and returns the proxy registered under key, if any.
+func (r *DynamicProxyRegistry) Delete(key string) (*ProxyClient, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	proxy, ok := r.proxies[key]
+	delete(r.proxies, key)
+	return proxy, ok
+}
+
+// List returns every currently registered proxy, so a newly created
+// *mcp.Server can advertise tools for MCPProxy objects that connected
+// before it existed (see mcp.WithDynamicProxyRegistry).
+func (r *DynamicProxyRegistry) List() []DynamicProxyEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]DynamicProxyEntry, 0, len(r.proxies))
+	for key, proxy := range r.proxies {
+		entries = append(entries, DynamicProxyEntry{Key: key, Client: proxy})
+	}
+	return entries
+}
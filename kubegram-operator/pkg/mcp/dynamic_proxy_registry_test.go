@@ -0,0 +1,53 @@
+package mcp
+
+import "testing"
+
+func TestDynamicProxyRegistry_SetDelete(t *testing.T) {
+	r := NewDynamicProxyRegistry()
+	proxy := &ProxyClient{Name: "ns/name"}
+
+	r.Set("ns/name", proxy)
+	got, ok := r.Delete("ns/name")
+	if !ok || got != proxy {
+		t.Fatalf("Delete = %v, %v; want %v, true", got, ok, proxy)
+	}
+
+	if _, ok := r.Delete("ns/name"); ok {
+		t.Error("expected the second Delete to report nothing found")
+	}
+}
+
+func TestDynamicProxyRegistry_SetReplacesExistingEntry(t *testing.T) {
+	r := NewDynamicProxyRegistry()
+	first := &ProxyClient{Name: "first"}
+	second := &ProxyClient{Name: "second"}
+
+	r.Set("ns/name", first)
+	r.Set("ns/name", second)
+
+	got, ok := r.Delete("ns/name")
+	if !ok || got != second {
+		t.Fatalf("Delete = %v, %v; want %v, true", got, ok, second)
+	}
+}
+
+func TestDynamicProxyRegistry_ListReturnsEveryEntry(t *testing.T) {
+	r := NewDynamicProxyRegistry()
+	a := &ProxyClient{Name: "a"}
+	b := &ProxyClient{Name: "b"}
+	r.Set("ns/a", a)
+	r.Set("ns/b", b)
+
+	entries := r.List()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+
+	byKey := map[string]*ProxyClient{}
+	for _, e := range entries {
+		byKey[e.Key] = e.Client
+	}
+	if byKey["ns/a"] != a || byKey["ns/b"] != b {
+		t.Errorf("got entries %+v, want ns/a=%v ns/b=%v", byKey, a, b)
+	}
+}
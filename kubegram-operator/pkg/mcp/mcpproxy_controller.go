@@ -0,0 +1,243 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kubegramv1alpha1 "github.com/kubegram/kubegram-operator/api/v1alpha1"
+)
+
+// mcpProxyReconnectInterval bounds how long a failed MCPProxy connection
+// attempt waits before Reconcile is retried, when no further spec change
+// would otherwise trigger one.
+const mcpProxyReconnectInterval = 30 * time.Second
+
+// MCPProxyReconciler watches MCPProxy objects and maintains Registry, a
+// live DynamicProxyRegistry of upstream proxies, so mcp.NewServer can
+// advertise their tools without an operator restart. It reconnects a
+// proxy's ProxyClient on every spec change (Command/URL/Env/AuthSecretRef/
+// ToolPrefix); secret rotation alone doesn't trigger a reconcile, so a
+// caller rotating a referenced token also needs to touch the MCPProxy
+// object (e.g. bump an annotation) to pick it up.
+type MCPProxyReconciler struct {
+	client.Client
+	Registry *DynamicProxyRegistry
+
+	// SuperviseCtx bounds the lifetime of the per-proxy Supervise
+	// goroutines Reconcile starts (see teardown), independent of any single
+	// Reconcile call's own ctx. Set it to the operator's long-lived
+	// background context; if nil, Supervise is not started and a dropped
+	// connection stays down until the next spec change forces a Reconcile.
+	SuperviseCtx context.Context
+
+	// OnConnect, if set, is called after proxy is newly connected, or
+	// reconnected by Supervise after a health-check failure, so a caller
+	// tracking live *mcp.Server instances can register its tools
+	// immediately; see RegisterProxyTools and cmd/manager/main.go.
+	OnConnect func(key string, proxy *ProxyClient)
+	// OnDisconnect, if set, is called with a proxy that is about to be
+	// replaced, disabled or removed, before it is closed, so a caller can
+	// unregister its tools from every live server first; see
+	// UnregisterProxyTools.
+	OnDisconnect func(key string, proxy *ProxyClient)
+
+	cancelsMu sync.Mutex
+	cancels   map[string]context.CancelFunc
+}
+
+// Reconcile brings Registry's entry for req in line with the named
+// MCPProxy object: absent or spec.enabled=false tears down any existing
+// connection, otherwise it (re)connects per spec.transport and records the
+// result in status.
+func (r *MCPProxyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	key := req.NamespacedName.String()
+
+	var obj kubegramv1alpha1.MCPProxy
+	if err := r.Get(ctx, req.NamespacedName, &obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.teardown(key)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if obj.Spec.Enabled != nil && !*obj.Spec.Enabled {
+		r.teardown(key)
+		return r.setStatus(ctx, &obj, kubegramv1alpha1.MCPProxyPhaseDisabled, "")
+	}
+
+	// A failed token lookup or connect attempt below leaves whatever proxy
+	// is already registered for key (if any) running untouched — a typo'd
+	// spec edit shouldn't take down a previously-working connection, only
+	// block a successful replacement of it.
+	token, err := r.resolveToken(ctx, obj.Namespace, obj.Spec.AuthSecretRef)
+	if err != nil {
+		return r.setStatus(ctx, &obj, kubegramv1alpha1.MCPProxyPhaseDisconnected, err.Error())
+	}
+
+	proxy, err := r.connect(ctx, &obj, token)
+	if err != nil {
+		logger.Error(err, "Failed to connect MCPProxy")
+		return r.setStatus(ctx, &obj, kubegramv1alpha1.MCPProxyPhaseDisconnected, err.Error())
+	}
+	proxy.ToolPrefix = obj.Spec.ToolPrefix
+
+	r.teardown(key)
+	r.Registry.Set(key, proxy)
+	if r.OnConnect != nil {
+		r.OnConnect(key, proxy)
+	}
+	if r.SuperviseCtx != nil {
+		superviseCtx, cancel := context.WithCancel(r.SuperviseCtx)
+		r.cancelsMu.Lock()
+		if r.cancels == nil {
+			r.cancels = map[string]context.CancelFunc{}
+		}
+		r.cancels[key] = cancel
+		r.cancelsMu.Unlock()
+
+		go proxy.Supervise(superviseCtx, DefaultProxyPingInterval, func(name string, refreshed []*mcp.Tool) {
+			if r.OnConnect != nil {
+				r.OnConnect(key, proxy)
+			}
+		})
+	}
+
+	tools := proxy.Tools()
+	toolNames := make([]string, 0, len(tools))
+	for _, t := range tools {
+		toolNames = append(toolNames, proxy.ToolPrefix+t.Name)
+	}
+
+	return r.setStatusTools(ctx, &obj, kubegramv1alpha1.MCPProxyPhaseConnected, "", toolNames)
+}
+
+// teardown stops key's Supervise goroutine (if Reconcile started one) and
+// removes and closes its current proxy, if any, notifying OnDisconnect
+// first so a caller can unregister its tools while the connection (and its
+// cached tool set) is still valid.
+func (r *MCPProxyReconciler) teardown(key string) {
+	r.cancelsMu.Lock()
+	if cancel, ok := r.cancels[key]; ok {
+		cancel()
+		delete(r.cancels, key)
+	}
+	r.cancelsMu.Unlock()
+
+	old, ok := r.Registry.Delete(key)
+	if !ok {
+		return
+	}
+	if r.OnDisconnect != nil {
+		r.OnDisconnect(key, old)
+	}
+	old.Close()
+}
+
+// resolveToken reads the bearer token AuthSecretRef points at, defaulting
+// its key to "token" when unset. Returns "" if ref is nil.
+func (r *MCPProxyReconciler) resolveToken(ctx context.Context, namespace string, ref *corev1.SecretKeySelector) (string, error) {
+	if ref == nil {
+		return "", nil
+	}
+	key := ref.Key
+	if key == "" {
+		key = "token"
+	}
+
+	var secret corev1.Secret
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, &secret); err != nil {
+		return "", fmt.Errorf("failed to fetch authSecretRef %s/%s: %w", namespace, ref.Name, err)
+	}
+	token, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("secret %s/%s has no key %q", namespace, ref.Name, key)
+	}
+	return string(token), nil
+}
+
+// connect dials obj's upstream per spec.transport, injecting token as
+// MCP_PROXY_TOKEN (stdio) or a bearer Authorization header (sse, websocket).
+func (r *MCPProxyReconciler) connect(ctx context.Context, obj *kubegramv1alpha1.MCPProxy, token string) (*ProxyClient, error) {
+	name := fmt.Sprintf("%s/%s", obj.Namespace, obj.Name)
+
+	switch obj.Spec.Transport {
+	case kubegramv1alpha1.MCPProxyTransportStdio:
+		if len(obj.Spec.Command) == 0 {
+			return nil, fmt.Errorf("transport %q requires spec.command", obj.Spec.Transport)
+		}
+		env := make([]string, 0, len(obj.Spec.Env)+1)
+		for _, e := range obj.Spec.Env {
+			env = append(env, e.Name+"="+e.Value)
+		}
+		if token != "" {
+			env = append(env, "MCP_PROXY_TOKEN="+token)
+		}
+		return NewStdioProxyClient(ctx, name, obj.Spec.Command[0], obj.Spec.Command[1:], WithStdioEnv(env...))
+
+	case kubegramv1alpha1.MCPProxyTransportSSE:
+		if obj.Spec.URL == "" {
+			return nil, fmt.Errorf("transport %q requires spec.url", obj.Spec.Transport)
+		}
+		var opts []SSEProxyClientOption
+		if token != "" {
+			opts = append(opts, WithSSEBearerToken(token))
+		}
+		return NewSSEProxyClient(ctx, name, obj.Spec.URL, opts...)
+
+	case kubegramv1alpha1.MCPProxyTransportWebSocket:
+		if obj.Spec.URL == "" {
+			return nil, fmt.Errorf("transport %q requires spec.url", obj.Spec.Transport)
+		}
+		var opts []WebSocketProxyClientOption
+		if token != "" {
+			opts = append(opts, WithWebSocketBearerToken(token))
+		}
+		return NewWebSocketProxyClient(ctx, name, obj.Spec.URL, opts...)
+
+	default:
+		return nil, fmt.Errorf("unknown transport %q", obj.Spec.Transport)
+	}
+}
+
+// setStatus updates obj's status with phase/message and clears Tools.
+func (r *MCPProxyReconciler) setStatus(ctx context.Context, obj *kubegramv1alpha1.MCPProxy, phase kubegramv1alpha1.MCPProxyPhase, message string) (ctrl.Result, error) {
+	return r.setStatusTools(ctx, obj, phase, message, nil)
+}
+
+// setStatusTools updates obj's status and, for MCPProxyPhaseDisconnected,
+// schedules a retry after mcpProxyReconnectInterval since nothing else will
+// otherwise trigger another reconcile of an unchanged object.
+func (r *MCPProxyReconciler) setStatusTools(ctx context.Context, obj *kubegramv1alpha1.MCPProxy, phase kubegramv1alpha1.MCPProxyPhase, message string, tools []string) (ctrl.Result, error) {
+	obj.Status.Phase = phase
+	obj.Status.Message = message
+	obj.Status.Tools = tools
+	if err := r.Status().Update(ctx, obj); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update MCPProxy status: %w", err)
+	}
+	if phase == kubegramv1alpha1.MCPProxyPhaseDisconnected {
+		return ctrl.Result{RequeueAfter: mcpProxyReconnectInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager registers the reconciler with mgr, watching MCPProxy
+// objects and ignoring status-only updates (which don't bump Generation).
+func (r *MCPProxyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kubegramv1alpha1.MCPProxy{}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kubegramv1alpha1 "github.com/kubegram/kubegram-operator/api/v1alpha1"
+)
+
+func newFakeReconciler(t *testing.T, objs ...runtime.Object) *MCPProxyReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme: %v", err)
+	}
+	if err := kubegramv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme: %v", err)
+	}
+	return &MCPProxyReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build(),
+		Registry: NewDynamicProxyRegistry(),
+	}
+}
+
+func TestResolveToken_NilRefReturnsEmptyToken(t *testing.T) {
+	r := newFakeReconciler(t)
+	token, err := r.resolveToken(context.Background(), "default", nil)
+	if err != nil {
+		t.Fatalf("resolveToken: %v", err)
+	}
+	if token != "" {
+		t.Errorf("got token %q, want empty", token)
+	}
+}
+
+func TestResolveToken_DefaultsKeyToToken(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "upstream-secret"},
+		Data:       map[string][]byte{"token": []byte("s3cr3t")},
+	}
+	r := newFakeReconciler(t, secret)
+
+	token, err := r.resolveToken(context.Background(), "default", &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "upstream-secret"},
+	})
+	if err != nil {
+		t.Fatalf("resolveToken: %v", err)
+	}
+	if token != "s3cr3t" {
+		t.Errorf("got token %q, want %q", token, "s3cr3t")
+	}
+}
+
+func TestResolveToken_MissingKeyErrors(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "upstream-secret"},
+		Data:       map[string][]byte{"other": []byte("x")},
+	}
+	r := newFakeReconciler(t, secret)
+
+	if _, err := r.resolveToken(context.Background(), "default", &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "upstream-secret"},
+	}); err == nil {
+		t.Error("expected an error when the secret has no \"token\" key")
+	}
+}
+
+func TestResolveToken_MissingSecretErrors(t *testing.T) {
+	r := newFakeReconciler(t)
+	if _, err := r.resolveToken(context.Background(), "default", &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "does-not-exist"},
+	}); err == nil {
+		t.Error("expected an error when the referenced secret doesn't exist")
+	}
+}
+
+func TestConnect_StdioRequiresCommand(t *testing.T) {
+	r := newFakeReconciler(t)
+	obj := &kubegramv1alpha1.MCPProxy{Spec: kubegramv1alpha1.MCPProxySpec{
+		Transport: kubegramv1alpha1.MCPProxyTransportStdio,
+	}}
+	if _, err := r.connect(context.Background(), obj, ""); err == nil {
+		t.Error("expected an error when transport=stdio and spec.command is empty")
+	}
+}
+
+func TestConnect_SSERequiresURL(t *testing.T) {
+	r := newFakeReconciler(t)
+	obj := &kubegramv1alpha1.MCPProxy{Spec: kubegramv1alpha1.MCPProxySpec{
+		Transport: kubegramv1alpha1.MCPProxyTransportSSE,
+	}}
+	if _, err := r.connect(context.Background(), obj, ""); err == nil {
+		t.Error("expected an error when transport=sse and spec.url is empty")
+	}
+}
+
+func TestConnect_UnknownTransportErrors(t *testing.T) {
+	r := newFakeReconciler(t)
+	obj := &kubegramv1alpha1.MCPProxy{Spec: kubegramv1alpha1.MCPProxySpec{
+		Transport: "carrier-pigeon",
+	}}
+	if _, err := r.connect(context.Background(), obj, ""); err == nil {
+		t.Error("expected an error for an unknown transport")
+	}
+}
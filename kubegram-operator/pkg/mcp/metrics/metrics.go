@@ -0,0 +1,100 @@
+// Package metrics defines and registers the Prometheus collectors the
+// operator exposes for MCP tool invocations and upstream proxy health,
+// served alongside controller-runtime's own metrics at
+// --metrics-bind-address.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// ToolCallsTotal counts every MCP tool call dispatched through
+	// WrapTool, by tool, upstream ("local" or a ProxyClient.Name) and
+	// result ("ok"/"error").
+	ToolCallsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kubegram_mcp_tool_calls_total",
+		Help: "Total MCP tool calls, by tool, upstream and result.",
+	}, []string{"tool", "upstream", "result"})
+
+	// ToolDurationSeconds observes how long a tool call took to return, by
+	// tool and upstream.
+	ToolDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kubegram_mcp_tool_duration_seconds",
+		Help:    "MCP tool call latency in seconds, by tool and upstream.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"tool", "upstream"})
+
+	// UpstreamUp reflects the outcome of the most recent liveness check
+	// (ProxyClient.Supervise's periodic ListTools ping) for an upstream, 1
+	// for up and 0 for down.
+	UpstreamUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kubegram_mcp_upstream_up",
+		Help: "Whether the upstream MCP proxy's last liveness check succeeded (1) or not (0).",
+	}, []string{"upstream"})
+
+	// WebSocketReconnectsTotal counts every time websocketRunnable.Start
+	// (cmd/manager/runnables.go) redials llm-websocket-url after a disconnect.
+	WebSocketReconnectsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "kubegram_mcp_websocket_reconnects_total",
+		Help: "Total reconnect attempts by the LLM WebSocket MCP transport.",
+	})
+
+	// ActiveSessions tracks currently open MCP sessions, by transport
+	// (stdio/http/websocket).
+	ActiveSessions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kubegram_mcp_active_sessions",
+		Help: "Currently active MCP sessions, by transport.",
+	}, []string{"transport"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(ToolCallsTotal, ToolDurationSeconds, UpstreamUp, WebSocketReconnectsTotal, ActiveSessions)
+}
+
+// TrackSessions wraps next so ActiveSessions(transport) counts one session
+// for as long as a single call to next's ServeHTTP is in flight —
+// appropriate for the SSE handler, whose ServeHTTP blocks for the life of
+// the connection rather than returning once the request is handled.
+func TrackSessions(transport string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ActiveSessions.WithLabelValues(transport).Inc()
+		defer ActiveSessions.WithLabelValues(transport).Dec()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// SetUpstreamUp records the outcome of upstream's most recent liveness
+// check.
+func SetUpstreamUp(upstream string, up bool) {
+	if up {
+		UpstreamUp.WithLabelValues(upstream).Set(1)
+	} else {
+		UpstreamUp.WithLabelValues(upstream).Set(0)
+	}
+}
+
+// WrapTool measures every call to next, observing its latency under
+// ToolDurationSeconds and counting it under ToolCallsTotal, labeled
+// "error" if next returns an error or an IsError result and "ok"
+// otherwise. Unlike mcpauth.WrapTool and policy.Policy.Wrap, this wrapping
+// is unconditional — metrics are not an opt-in feature.
+func WrapTool(upstream, toolName string, next func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error)) func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := next(ctx, request)
+		ToolDurationSeconds.WithLabelValues(toolName, upstream).Observe(time.Since(start).Seconds())
+		resultLabel := "ok"
+		if err != nil || (result != nil && result.IsError) {
+			resultLabel = "error"
+		}
+		ToolCallsTotal.WithLabelValues(toolName, upstream, resultLabel).Inc()
+		return result, err
+	}
+}
@@ -3,75 +3,302 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
+	"os"
 	"os/exec"
+	"sync"
+	"time"
 
+	"github.com/kubegram/kubegram-operator/pkg/mcp/metrics"
+	"github.com/kubegram/kubegram-operator/pkg/transport"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	proxyRetryInitial    = 1 * time.Second
+	proxyRetryMax        = 30 * time.Second
+	proxyRetryMultiplier = 2.0
+	proxyResetWindow     = 30 * time.Minute
 )
 
 // ProxyClient represents a connection to a remote MCP server
 // It manages the lifecycle of the connection and forwards requests.
+// Once created via NewStdioProxyClient or NewSSEProxyClient, it also knows
+// how to redial/respawn itself, so Supervise can recover it if the
+// downstream server crashes or drops the connection.
 type ProxyClient struct {
 	Client  *mcp.Client
 	Session *mcp.ClientSession
 	Name    string // Name of the proxied service (e.g., "argo", "k8s")
+
+	// ToolPrefix, if set, is prepended to every tool name this proxy
+	// advertises (see registerProxyToolSet); set it before the proxy's
+	// tools are first registered on a server. Used by MCPProxyReconciler to
+	// apply MCPProxySpec.ToolPrefix.
+	ToolPrefix string
+
+	mu          sync.Mutex
+	dial        func(ctx context.Context) (mcp.Transport, error)
+	tools       []*mcp.Tool
+	authChecker *transport.AuthChecker
+	lastErr     error
+}
+
+// LastError returns the error from this proxy's most recent failed health
+// check or (re)connect attempt, or nil if its last attempt succeeded. Used
+// by the operator's /debug/mcp/proxies dump.
+func (p *ProxyClient) LastError() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lastErr
+}
+
+func (p *ProxyClient) setLastErr(err error) {
+	p.mu.Lock()
+	p.lastErr = err
+	p.mu.Unlock()
+}
+
+// SetAuthChecker configures periodic re-authorization for this proxy's
+// session, mirroring transport.WebSocketTransport.SetAuthChecker: every
+// checker.Interval, Supervise runs checker.Check and compares its result
+// against checker.Template. A mismatch (or a checker.Check error) tears
+// down and respawns/redials the session the same way a failed health check
+// does. Call it before Supervise.
+func (p *ProxyClient) SetAuthChecker(checker *transport.AuthChecker) {
+	p.mu.Lock()
+	p.authChecker = checker
+	p.mu.Unlock()
+}
+
+// StdioProxyClientOption configures NewStdioProxyClient.
+type StdioProxyClientOption func(*stdioProxyClientConfig)
+
+type stdioProxyClientConfig struct {
+	env []string
+}
+
+// WithStdioEnv appends "KEY=VALUE" entries to the subprocess's environment,
+// on top of the operator's own (os.Environ()). Used by MCPProxyReconciler
+// to pass MCPProxySpec.Env and an MCPProxySpec.AuthSecretRef-derived token.
+func WithStdioEnv(env ...string) StdioProxyClientOption {
+	return func(c *stdioProxyClientConfig) { c.env = append(c.env, env...) }
 }
 
 // NewStdioProxyClient creates a new client connection to an external MCP server via stdio
 // It spawns the external server command (e.g., "npx ...") and connects to its stdin/stdout.
-func NewStdioProxyClient(ctx context.Context, name string, command string, args []string) (*ProxyClient, error) {
-	// Create a command transport
-	// This transport runs the command and uses its stdin/stdout for communication.
-	cmd := exec.Command(command, args...)
-	transport := &mcp.CommandTransport{
-		Command: cmd,
+func NewStdioProxyClient(ctx context.Context, name string, command string, args []string, opts ...StdioProxyClientOption) (*ProxyClient, error) {
+	cfg := &stdioProxyClientConfig{}
+	for _, opt := range opts {
+		opt(cfg)
 	}
 
-	return newProxyClientWithTransport(ctx, name, transport)
+	// Each (re)connect needs its own *exec.Cmd, since a Cmd can't be re-run
+	// once it has exited, so the dialer builds one fresh every time.
+	dial := func(ctx context.Context) (mcp.Transport, error) {
+		cmd := exec.Command(command, args...)
+		if len(cfg.env) > 0 {
+			cmd.Env = append(os.Environ(), cfg.env...)
+		}
+		return &mcp.CommandTransport{Command: cmd}, nil
+	}
+
+	return newProxyClientWithDialer(ctx, name, dial)
+}
+
+// SSEProxyClientOption configures NewSSEProxyClient.
+type SSEProxyClientOption func(*sseProxyClientConfig)
+
+type sseProxyClientConfig struct {
+	proxyURL    *url.URL
+	bearerToken string
+}
+
+// WithSSEProxyURL routes the SSE connection through proxyURL (an http(s)://
+// or socks5:// egress proxy) instead of honoring the environment's
+// HTTP_PROXY/HTTPS_PROXY/NO_PROXY (the default, via http.ProxyFromEnvironment).
+func WithSSEProxyURL(proxyURL *url.URL) SSEProxyClientOption {
+	return func(c *sseProxyClientConfig) { c.proxyURL = proxyURL }
+}
+
+// WithSSEBearerToken sends "Authorization: Bearer <token>" on every request
+// to the upstream SSE endpoint. Used by MCPProxyReconciler to apply an
+// MCPProxySpec.AuthSecretRef-derived token.
+func WithSSEBearerToken(token string) SSEProxyClientOption {
+	return func(c *sseProxyClientConfig) { c.bearerToken = token }
+}
+
+// bearerTokenTransport adds "Authorization: Bearer <token>" to every
+// request before delegating to base.
+type bearerTokenTransport struct {
+	token string
+	base  http.RoundTripper
+}
+
+func (t *bearerTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.base.RoundTrip(req)
 }
 
 // NewSSEProxyClient creates a new client connection to an external MCP server via SSE
-func NewSSEProxyClient(ctx context.Context, name string, url string) (*ProxyClient, error) {
-	transport := &mcp.SSEClientTransport{
-		Endpoint: url,
+func NewSSEProxyClient(ctx context.Context, name string, endpoint string, opts ...SSEProxyClientOption) (*ProxyClient, error) {
+	cfg := &sseProxyClientConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	proxyFunc := http.ProxyFromEnvironment
+	if cfg.proxyURL != nil {
+		proxyFunc = http.ProxyURL(cfg.proxyURL)
+	}
+
+	var rt http.RoundTripper = &http.Transport{Proxy: proxyFunc}
+	if cfg.bearerToken != "" {
+		rt = &bearerTokenTransport{token: cfg.bearerToken, base: rt}
+	}
+
+	dial := func(ctx context.Context) (mcp.Transport, error) {
+		return &mcp.SSEClientTransport{
+			Endpoint:   endpoint,
+			HTTPClient: &http.Client{Transport: rt},
+		}, nil
+	}
+
+	return newProxyClientWithDialer(ctx, name, dial)
+}
+
+// WebSocketProxyClientOption configures NewWebSocketProxyClient.
+type WebSocketProxyClientOption func(*webSocketProxyClientConfig)
+
+type webSocketProxyClientConfig struct {
+	proxyURL    *url.URL
+	bearerToken string
+}
+
+// WithWebSocketProxyURL routes the connection through proxyURL (an
+// http(s):// or socks5:// egress proxy) instead of honoring the
+// environment's HTTP_PROXY/HTTPS_PROXY/NO_PROXY; see
+// transport.WebSocketTransport.ProxyURL.
+func WithWebSocketProxyURL(proxyURL *url.URL) WebSocketProxyClientOption {
+	return func(c *webSocketProxyClientConfig) { c.proxyURL = proxyURL }
+}
+
+// WithWebSocketBearerToken sends "Authorization: Bearer <token>" on the
+// handshake request for every dial (initial connect and each reconnect),
+// the same way WithSSEBearerToken does for the SSE transport.
+func WithWebSocketBearerToken(token string) WebSocketProxyClientOption {
+	return func(c *webSocketProxyClientConfig) { c.bearerToken = token }
+}
+
+// NewWebSocketProxyClient creates a new client connection to an external
+// MCP server via a websocket.
+func NewWebSocketProxyClient(ctx context.Context, name string, wsURL string, opts ...WebSocketProxyClientOption) (*ProxyClient, error) {
+	cfg := &webSocketProxyClientConfig{}
+	for _, opt := range opts {
+		opt(cfg)
 	}
 
-	return newProxyClientWithTransport(ctx, name, transport)
+	dial := func(ctx context.Context) (mcp.Transport, error) {
+		t := transport.NewWebSocketTransport(wsURL)
+		t.ProxyURL = cfg.proxyURL
+		if cfg.bearerToken != "" {
+			t.Header = http.Header{"Authorization": []string{"Bearer " + cfg.bearerToken}}
+		}
+		return t, nil
+	}
+
+	return newProxyClientWithDialer(ctx, name, dial)
 }
 
-func newProxyClientWithTransport(ctx context.Context, name string, transport mcp.Transport) (*ProxyClient, error) {
-	// Create the client
+func newProxyClientWithDialer(ctx context.Context, name string, dial func(ctx context.Context) (mcp.Transport, error)) (*ProxyClient, error) {
+	p := &ProxyClient{Name: name, dial: dial}
+	if err := p.connect(ctx); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// connect (re)dials the proxied server via p.dial, swaps in the new
+// client/session and refreshes the cached tool set. It backs both the
+// initial connection and the reconnects Supervise performs after a health
+// check failure.
+func (p *ProxyClient) connect(ctx context.Context) error {
+	tr, err := p.dial(ctx)
+	if err != nil {
+		err = fmt.Errorf("failed to build transport for %s: %w", p.Name, err)
+		p.setLastErr(err)
+		return err
+	}
+
 	impl := &mcp.Implementation{
 		Name:    "kubegram-operator-proxy",
 		Version: "0.1.0",
 	}
-
 	client := mcp.NewClient(impl, nil)
 
-	// Connect to the server
-	// This establishes the session with the external MCP server.
-	session, err := client.Connect(ctx, transport, nil)
+	session, err := client.Connect(ctx, tr, nil)
+	if err != nil {
+		err = fmt.Errorf("failed to connect to %s mcp server: %w", p.Name, err)
+		p.setLastErr(err)
+		return err
+	}
+
+	toolsList, err := listTools(ctx, session, p.Name)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to %s mcp server: %w", name, err)
+		session.Close()
+		p.setLastErr(err)
+		return err
 	}
 
-	return &ProxyClient{
-		Client:  client,
-		Session: session,
-		Name:    name,
-	}, nil
+	p.mu.Lock()
+	oldSession := p.Session
+	p.Client = client
+	p.Session = session
+	p.tools = toolsList
+	p.lastErr = nil
+	p.mu.Unlock()
+
+	if oldSession != nil {
+		oldSession.Close()
+	}
+	metrics.SetUpstreamUp(p.Name, true)
+	return nil
+}
+
+func (p *ProxyClient) session() *mcp.ClientSession {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.Session
+}
+
+// Tools returns the proxy's most recently refreshed tool set, as cached by
+// the last successful connect or Supervise health check. Unlike ListTools
+// it never makes a round trip to the downstream server.
+func (p *ProxyClient) Tools() []*mcp.Tool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*mcp.Tool(nil), p.tools...)
 }
 
 // ListTools returns the tools available on the proxied server
 // It delegates the ListTools call to the connected session.
 func (p *ProxyClient) ListTools(ctx context.Context) ([]*mcp.Tool, error) {
-	if p.Session == nil {
+	session := p.session()
+	if session == nil {
 		return nil, fmt.Errorf("session is not initialized for %s", p.Name)
 	}
 
+	return listTools(ctx, session, p.Name)
+}
+
+func listTools(ctx context.Context, session *mcp.ClientSession, name string) ([]*mcp.Tool, error) {
 	// ListTools takes *ListToolsParams
-	listToolsResult, err := p.Session.ListTools(ctx, &mcp.ListToolsParams{})
+	listToolsResult, err := session.ListTools(ctx, &mcp.ListToolsParams{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list tools from %s: %w", p.Name, err)
+		return nil, fmt.Errorf("failed to list tools from %s: %w", name, err)
 	}
 
 	return listToolsResult.Tools, nil
@@ -80,12 +307,13 @@ func (p *ProxyClient) ListTools(ctx context.Context) ([]*mcp.Tool, error) {
 // CallTool calls a tool on the proxied server
 // It delegates the CallTool call to the connected session, passing the arguments.
 func (p *ProxyClient) CallTool(ctx context.Context, toolName string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-	if p.Session == nil {
+	session := p.session()
+	if session == nil {
 		return nil, fmt.Errorf("session is not initialized for %s", p.Name)
 	}
 
 	// CallTool takes *CallToolParams
-	return p.Session.CallTool(ctx, &mcp.CallToolParams{
+	return session.CallTool(ctx, &mcp.CallToolParams{
 		Name:      toolName,
 		Arguments: arguments,
 	})
@@ -93,8 +321,92 @@ func (p *ProxyClient) CallTool(ctx context.Context, toolName string, arguments m
 
 // Close closes the connection to the proxied server
 func (p *ProxyClient) Close() error {
-	if p.Session != nil {
-		return p.Session.Close()
+	session := p.session()
+	if session != nil {
+		return session.Close()
 	}
 	return nil
 }
+
+// Supervise health-checks the proxy every pingInterval with a lightweight
+// ListTools call and, on failure, tears down the transport and
+// redials/respawns it (see NewStdioProxyClient/NewSSEProxyClient) with
+// capped exponential backoff, mirroring
+// transport.WebSocketTransport.Start: 1s→30s, reset back to 1s after 30
+// minutes of a stable connection. onReconnect, if non-nil, is called with
+// the proxy's name and its freshly refreshed tool set after every
+// successful (re)connect, so a caller can re-advertise the new set upward
+// (see RegisterProxyTools). Every health check and (re)connect attempt also
+// updates metrics.UpstreamUp for this proxy, so operators can alert on
+// sustained downtime instead of only seeing a log line. Supervise blocks
+// until ctx is done, so it's meant to be run in its own goroutine — see
+// ProxyManager.Start.
+func (p *ProxyClient) Supervise(ctx context.Context, pingInterval time.Duration, onReconnect func(name string, tools []*mcp.Tool)) {
+	logger := log.Log.WithName("mcp-proxy").WithValues("proxy", p.Name)
+	currentInterval := proxyRetryInitial
+	connectedAt := time.Now()
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	// armAuthChecker arms a fresh copy of p.authChecker (if set) for the
+	// current session, so Supervise notices if its credentials/scopes drift
+	// from what the session was opened with; see SetAuthChecker.
+	armAuthChecker := func() <-chan error {
+		p.mu.Lock()
+		checker := p.authChecker
+		p.mu.Unlock()
+		if checker == nil {
+			return nil
+		}
+		return checker.Clone().Start(ctx)
+	}
+	authChangedCh := armAuthChecker()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := p.ListTools(ctx); err == nil {
+				metrics.SetUpstreamUp(p.Name, true)
+				continue
+			} else {
+				metrics.SetUpstreamUp(p.Name, false)
+				p.setLastErr(err)
+				logger.Error(err, "Health check failed, reconnecting")
+			}
+		case err := <-authChangedCh:
+			logger.Info("Session authorization changed, reconnecting", "reason", err)
+		}
+
+		for {
+			if err := p.connect(ctx); err != nil {
+				metrics.SetUpstreamUp(p.Name, false)
+				logger.Error(err, "Failed to reconnect", "retry_after", currentInterval.String())
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(currentInterval):
+					currentInterval = time.Duration(float64(currentInterval) * proxyRetryMultiplier)
+					if currentInterval > proxyRetryMax {
+						currentInterval = proxyRetryMax
+					}
+					continue
+				}
+			}
+			break
+		}
+
+		logger.Info("Reconnected to MCP proxy")
+		if time.Since(connectedAt) > proxyResetWindow {
+			currentInterval = proxyRetryInitial
+		}
+		connectedAt = time.Now()
+		authChangedCh = armAuthChecker()
+
+		if onReconnect != nil {
+			onReconnect(p.Name, p.Tools())
+		}
+	}
+}
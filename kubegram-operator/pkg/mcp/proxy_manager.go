@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// DefaultProxyPingInterval is how often ProxyManager.Start health-checks
+// each proxy when a caller doesn't need a different cadence.
+const DefaultProxyPingInterval = 30 * time.Second
+
+// ProxyManager owns a set of ProxyClients, supervises their connections and
+// aggregates their currently known tool sets, so callers like NewServer
+// don't need to reason about individual proxy client lifecycles.
+type ProxyManager struct {
+	proxies []*ProxyClient
+}
+
+// NewProxyManager wraps proxies for supervision and aggregation.
+func NewProxyManager(proxies []*ProxyClient) *ProxyManager {
+	return &ProxyManager{proxies: proxies}
+}
+
+// Proxies returns the underlying proxy clients.
+func (m *ProxyManager) Proxies() []*ProxyClient {
+	return m.proxies
+}
+
+// Aggregate returns the current tool set across every proxy, as cached by
+// each ProxyClient's last successful connect or health check.
+func (m *ProxyManager) Aggregate() []*mcp.Tool {
+	var all []*mcp.Tool
+	for _, p := range m.proxies {
+		all = append(all, p.Tools()...)
+	}
+	return all
+}
+
+// Start launches a Supervise goroutine for every proxy, health-checking it
+// every pingInterval and reconnecting with backoff on failure. onChanged,
+// if non-nil, is called with a proxy's name and refreshed tool set after
+// every successful (re)connect, so a caller can re-register that proxy's
+// tools (e.g. via RegisterProxyTools) and let connected clients pick up the
+// change. Start returns immediately; supervision runs until ctx is done.
+func (m *ProxyManager) Start(ctx context.Context, pingInterval time.Duration, onChanged func(name string, tools []*mcp.Tool)) {
+	for _, p := range m.proxies {
+		go p.Supervise(ctx, pingInterval, onChanged)
+	}
+}
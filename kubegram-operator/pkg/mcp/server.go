@@ -7,7 +7,13 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/kubegram/kubegram-operator/pkg/carrier"
+	"github.com/kubegram/kubegram-operator/pkg/kube/contextmgr"
+	mcpauth "github.com/kubegram/kubegram-operator/pkg/mcp/auth"
+	mcpmetrics "github.com/kubegram/kubegram-operator/pkg/mcp/metrics"
 	"github.com/kubegram/kubegram-operator/pkg/tools"
+	"github.com/kubegram/kubegram-operator/pkg/tools/k8s"
+	"github.com/kubegram/kubegram-operator/pkg/tools/policy"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -15,24 +21,69 @@ import (
 type ServerOptions struct {
 	ArgoMCPCommand []string
 	ArgoMCPURL     string
+	ArgoMCPContext string
 	K8sMCPCommand  []string
 	K8sMCPURL      string
+	K8sMCPContext  string
 	HTTPAddr       string
+	// ExtraKubeconfigs are additional kubeconfig paths (beyond the default
+	// loading rules) that the native k8s tools' contextmgr.Manager should
+	// also load contexts from.
+	ExtraKubeconfigs []string
+	// Policy, if set, wraps every registered local and proxied tool with
+	// its allow/deny, read-only, timeout, output-size and audit-logging
+	// behavior.
+	Policy *policy.Policy
+	// Authorizer, if set, requires a SubjectAccessReview to allow every
+	// registered tool call; see WithAuthorizer.
+	Authorizer *mcpauth.Authorizer
+	// ArgoPortForward, if set and ArgoMCPURL/ArgoMCPCommand are both empty,
+	// reaches the Argo MCP server by opening a port-forward instead.
+	ArgoPortForward *ArgoPortForwardSpec
 }
 
-// InitProxies initializes the connections to upstream MCP servers
-func InitProxies(ctx context.Context, argoCmd []string, argoURL string, k8sCmd []string, k8sURL string) []*ProxyClient {
+// localUpstream is the mcpmetrics upstream label for every tool backed by
+// this operator's own code, as opposed to one forwarded to a ProxyClient
+// (labeled by its Name; see registerProxyToolSet).
+const localUpstream = "local"
+
+// ArgoPortForwardSpec tells InitProxies to reach the Argo MCP server by
+// opening a port-forward instead of requiring argoURL/argoCmd to be set.
+type ArgoPortForwardSpec struct {
+	Namespace  string
+	Target     string
+	RemotePort int
+	Context    string
+}
+
+// InitProxies initializes the connections to upstream MCP servers. argoContext
+// and k8sContext, when set, are passed as "--context <name>" to the upstream
+// command so a command-based MCP server (e.g. a k8s-mcp-server) targets a
+// specific cluster instead of its own ambient kubeconfig. If argoURL and
+// argoCmd are both empty and argoPortForward is set, the Argo MCP server is
+// reached by opening a port-forward through pfManager/pfRegistry instead.
+func InitProxies(ctx context.Context, argoCmd []string, argoURL string, argoContext string, k8sCmd []string, k8sURL string, k8sContext string, pfManager *contextmgr.Manager, pfRegistry *tools.PortForwardRegistry, argoPortForward *ArgoPortForwardSpec) []*ProxyClient {
 	proxies := []*ProxyClient{}
 
 	// Helper to setup proxy
-	setupProxy := func(name string, cmdArgs []string, url string) {
+	setupProxy := func(name string, cmdArgs []string, url string, kubeContext string) {
 		var proxy *ProxyClient
 		var err error
 
 		if url != "" {
 			proxy, err = NewSSEProxyClient(ctx, name, url)
 		} else if len(cmdArgs) > 0 {
+			if kubeContext != "" {
+				cmdArgs = append(append([]string{}, cmdArgs...), "--context", kubeContext)
+			}
 			proxy, err = NewStdioProxyClient(ctx, name, cmdArgs[0], cmdArgs[1:])
+		} else if name == "argo" && argoPortForward != nil && pfManager != nil && pfRegistry != nil {
+			url, err = openArgoPortForward(ctx, pfManager, pfRegistry, *argoPortForward)
+			if err != nil {
+				log.Printf("Warning: Failed to port-forward to %s MCP server: %v", name, err)
+				return
+			}
+			proxy, err = NewSSEProxyClient(ctx, name, url)
 		} else {
 			return
 		}
@@ -45,14 +96,101 @@ func InitProxies(ctx context.Context, argoCmd []string, argoURL string, k8sCmd [
 		log.Printf("Connected to %s MCP proxy", name)
 	}
 
-	setupProxy("argo", argoCmd, argoURL)
-	setupProxy("k8s", k8sCmd, k8sURL)
+	setupProxy("argo", argoCmd, argoURL, argoContext)
+	setupProxy("k8s", k8sCmd, k8sURL, k8sContext)
 
 	return proxies
 }
 
-// NewServer creates a new MCP server instance with all tools registered
-func NewServer(ctx context.Context, proxies []*ProxyClient) *mcp.Server {
+// openArgoPortForward opens a long-lived (duration 0) port-forward to the
+// Argo MCP server and returns the local SSE URL to reach it on.
+func openArgoPortForward(ctx context.Context, manager *contextmgr.Manager, registry *tools.PortForwardRegistry, spec ArgoPortForwardSpec) (string, error) {
+	client, err := manager.Resolve(spec.Context)
+	if err != nil {
+		return "", err
+	}
+	status, err := registry.Start(ctx, client, spec.Namespace, spec.Target, spec.RemotePort, 0, 0)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("http://127.0.0.1:%d/sse", status.LocalPort), nil
+}
+
+// NewServerOption configures optional behavior of NewServer.
+type NewServerOption func(*serverConfig)
+
+type serverConfig struct {
+	extraKubeconfigs     []string
+	policy               *policy.Policy
+	authorizer           *mcpauth.Authorizer
+	pfRegistry           *tools.PortForwardRegistry
+	carrierRegistry      *carrier.Registry
+	dynamicProxyRegistry *DynamicProxyRegistry
+}
+
+// WithExtraKubeconfigs adds kubeconfig paths (beyond the default loading
+// rules) that the native k8s tools' contextmgr.Manager should also load
+// contexts from.
+func WithExtraKubeconfigs(paths ...string) NewServerOption {
+	return func(c *serverConfig) { c.extraKubeconfigs = paths }
+}
+
+// WithPolicy wraps every registered local and proxied tool with p,
+// enforcing p's allow/deny rules, read-only mode, per-call timeout, output
+// truncation and audit logging. Without this option tools are registered
+// unwrapped, matching the server's behavior before policy support existed.
+func WithPolicy(p *policy.Policy) NewServerOption {
+	return func(c *serverConfig) { c.policy = p }
+}
+
+// WithAuthorizer requires a SubjectAccessReview against a to pass before
+// every registered tool call is dispatched, using the mcpauth.Identity
+// stashed on the call's context by mcpauth.Middleware (see
+// cmd/manager/main.go's HTTP/SSE server setup). Without this option tools
+// are registered without an authorization check, matching the server's
+// behavior before auth support existed — appropriate for the stdio and
+// carrier-CLI transports, which already run as whoever the operator
+// process runs as.
+func WithAuthorizer(a *mcpauth.Authorizer) NewServerOption {
+	return func(c *serverConfig) { c.authorizer = a }
+}
+
+// WithPortForwardRegistry registers the port_forward/list_port_forwards/
+// stop_port_forward tools against the given registry instead of a fresh
+// one, so forwards InitProxies opened to reach an upstream MCP server (see
+// ArgoPortForwardSpec) show up in list_port_forwards alongside
+// caller-initiated ones.
+func WithPortForwardRegistry(r *tools.PortForwardRegistry) NewServerOption {
+	return func(c *serverConfig) { c.pfRegistry = r }
+}
+
+// WithCarrierRegistry advertises a "port-forward-<id>" tool (see pkg/carrier)
+// for every carrier already registered in r at server construction time, so
+// a server created after a kubegram carrier CLI has already connected still
+// exposes its tool. A caller also needs to add/remove the tool on this
+// server directly as carriers come and go after construction; see
+// cmd/manager/main.go's onCarrierRegister.
+func WithCarrierRegistry(r *carrier.Registry) NewServerOption {
+	return func(c *serverConfig) { c.carrierRegistry = r }
+}
+
+// WithDynamicProxyRegistry advertises every proxy already tracked in r (see
+// MCPProxyReconciler) at server construction time, so a server created
+// after an MCPProxy object already exists still exposes its tools. A
+// caller also needs to add/remove tools on this server directly as MCPProxy
+// objects come and go after construction; see
+// MCPProxyReconciler.OnConnect/OnDisconnect and cmd/manager/main.go.
+func WithDynamicProxyRegistry(r *DynamicProxyRegistry) NewServerOption {
+	return func(c *serverConfig) { c.dynamicProxyRegistry = r }
+}
+
+// NewServer creates a new MCP server instance with all tools registered.
+func NewServer(ctx context.Context, proxies []*ProxyClient, opts ...NewServerOption) *mcp.Server {
+	cfg := &serverConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	// Create the server
 	server := mcp.NewServer(&mcp.Implementation{
 		Name:    "kubegram-operator",
@@ -61,51 +199,249 @@ func NewServer(ctx context.Context, proxies []*ProxyClient) *mcp.Server {
 
 	// Register Local Tools
 	bashTool := tools.NewBashTool()
-	server.AddTool(&bashTool, tools.HandleBashCommand)
+	server.AddTool(&bashTool, wrapBash(cfg.policy, cfg.authorizer, tools.HandleBashCommand))
 
+	// kubectl is kept registered as a fallback for anything the native k8s
+	// tools below don't yet cover, but the native tools are preferred.
 	kubectlTool := tools.NewKubectlTool()
-	server.AddTool(&kubectlTool, tools.HandleKubectlCommand)
+	server.AddTool(&kubectlTool, wrapKubectl(cfg.policy, cfg.authorizer, tools.HandleKubectlCommand))
 
 	argoTool := tools.NewArgoCDInstallerTool()
-	server.AddTool(&argoTool, tools.HandleArgoCDInstall)
+	server.AddTool(&argoTool, wrapTool(cfg.policy, cfg.authorizer, localUpstream, "install_argo_mcp", tools.HandleArgoCDInstall))
+
+	registerK8sTools(server, cfg.extraKubeconfigs, cfg.policy, cfg.authorizer)
+	registerHelmTools(server, cfg.policy, cfg.authorizer)
+	registerPortForwardTools(server, cfg.extraKubeconfigs, cfg.pfRegistry, cfg.policy, cfg.authorizer)
 
 	// Register Proxy Tools
 	for _, proxy := range proxies {
-		// Register proxied tools
 		remoteTools, err := proxy.ListTools(ctx)
 		if err != nil {
 			log.Printf("Warning: Failed to list tools from %s: %v", proxy.Name, err)
 			continue
 		}
+		registerProxyToolSet(server, cfg.policy, cfg.authorizer, proxy, remoteTools)
+	}
 
-		for _, tool := range remoteTools {
-			// We wrap the handler to call the proxy
-			p := proxy
-			tName := tool.Name
-
-			server.AddTool(tool, func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-				// We need to pass arguments as map[string]interface{} to the proxy
-				// But request.Params.Arguments is json.RawMessage
-				var args map[string]interface{}
-				if len(request.Params.Arguments) > 0 {
-					if err := json.Unmarshal(request.Params.Arguments, &args); err != nil {
-						return nil, fmt.Errorf("failed to unmarshal arguments for proxy: %w", err)
-					}
-				}
-				return p.CallTool(ctx, tName, args)
-			})
-			log.Printf("Registered proxied tool: %s (from %s)", tool.Name, proxy.Name)
+	// Register Dynamic Proxy Tools (MCPProxy CRD objects already connected
+	// by MCPProxyReconciler; see WithDynamicProxyRegistry)
+	if cfg.dynamicProxyRegistry != nil {
+		for _, entry := range cfg.dynamicProxyRegistry.List() {
+			registerProxyToolSet(server, cfg.policy, cfg.authorizer, entry.Client, entry.Client.Tools())
+		}
+	}
+
+	// Register Carrier Tools
+	if cfg.carrierRegistry != nil {
+		carrierManager := carrier.NewManager()
+		for _, entry := range cfg.carrierRegistry.List() {
+			carrierManager.AddTool(server, entry.ID, entry.Name, entry.Conn, cfg.authorizer)
 		}
 	}
 
 	return server
 }
 
+// registerProxyToolSet registers each of remoteTools on server as a tool
+// that forwards calls to proxy, wrapped with p's policy the same way local
+// tools are. The tool is advertised locally as proxy.ToolPrefix+tool.Name,
+// so upstreams exposing a same-named tool don't collide, while the upstream
+// CallTool is still invoked by its original, unprefixed name.
+func registerProxyToolSet(server *mcp.Server, p *policy.Policy, a *mcpauth.Authorizer, proxy *ProxyClient, remoteTools []*mcp.Tool) {
+	for _, remoteTool := range remoteTools {
+		// We wrap the handler to call the proxy
+		proxy := proxy
+		upstreamName := remoteTool.Name
+		localName := proxy.ToolPrefix + upstreamName
+
+		handler := func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			// We need to pass arguments as map[string]interface{} to the proxy
+			// But request.Params.Arguments is json.RawMessage
+			var args map[string]interface{}
+			if len(request.Params.Arguments) > 0 {
+				if err := json.Unmarshal(request.Params.Arguments, &args); err != nil {
+					return nil, fmt.Errorf("failed to unmarshal arguments for proxy: %w", err)
+				}
+			}
+			return proxy.CallTool(ctx, upstreamName, args)
+		}
+
+		tool := *remoteTool
+		tool.Name = localName
+		server.AddTool(&tool, wrapTool(p, a, proxy.Name, localName, handler))
+		log.Printf("Registered proxied tool: %s (from %s)", localName, proxy.Name)
+	}
+}
+
+// UnregisterProxyTools removes proxy's most recently advertised tool set
+// (as cached by its last successful connect or Supervise health check)
+// from server. Call it when a proxy is being torn down for good — e.g. an
+// MCPProxy object deletion (see DynamicProxyRegistry) — unlike a
+// Supervise-driven reconnect, which keeps the same tools registered.
+func UnregisterProxyTools(server *mcp.Server, proxy *ProxyClient) {
+	for _, tool := range proxy.Tools() {
+		server.RemoveTools(proxy.ToolPrefix + tool.Name)
+	}
+}
+
+// RegisterProxyTools (re-)registers proxy's current tool set (as cached by
+// its last successful connect or Supervise health check) on server,
+// wrapped with p's policy. Call it directly, or from a ProxyManager.Start
+// onChanged callback, whenever Supervise reconnects a proxy whose tool set
+// may have changed — e.g. a respawned npx server exposing a newer tool
+// list. AddTool causes the underlying SDK to send a
+// notifications/tools/list_changed to every connected session, so clients
+// pick up the refreshed tools without reconnecting.
+func RegisterProxyTools(server *mcp.Server, p *policy.Policy, a *mcpauth.Authorizer, proxy *ProxyClient) {
+	registerProxyToolSet(server, p, a, proxy, proxy.Tools())
+}
+
+// wrapTool applies a's per-call SubjectAccessReview check (if a is non-nil)
+// and p's timeout/output/audit behavior (if p is non-nil) to handler,
+// innermost first, so a denied call is still recorded by p's audit log and
+// by mcpmetrics, which always wraps outermost so its latency/result
+// observations cover the full call, including a denial.
+func wrapTool(p *policy.Policy, a *mcpauth.Authorizer, upstream, name string, handler policy.ToolHandler) policy.ToolHandler {
+	handler = mcpauth.WrapTool(a, name, handler)
+	if p != nil {
+		handler = p.Wrap(name, handler)
+	}
+	return mcpmetrics.WrapTool(upstream, name, handler)
+}
+
+// wrapBash applies a's per-call SubjectAccessReview check and p's bash
+// allow/deny checks (plus the timeout/output/audit behavior) to handler,
+// then mcpmetrics' unconditional call metrics.
+func wrapBash(p *policy.Policy, a *mcpauth.Authorizer, handler policy.ToolHandler) policy.ToolHandler {
+	handler = mcpauth.WrapTool(a, "bash", handler)
+	if p != nil {
+		handler = p.WrapBash(handler)
+	}
+	return mcpmetrics.WrapTool(localUpstream, "bash", handler)
+}
+
+// wrapKubectl applies a's per-call SubjectAccessReview check and p's kubectl
+// allow/deny and read-only checks (plus the timeout/output/audit behavior)
+// to handler, then mcpmetrics' unconditional call metrics.
+func wrapKubectl(p *policy.Policy, a *mcpauth.Authorizer, handler policy.ToolHandler) policy.ToolHandler {
+	handler = mcpauth.WrapTool(a, "kubectl", handler)
+	if p != nil {
+		handler = p.WrapKubectl(handler)
+	}
+	return mcpmetrics.WrapTool(localUpstream, "kubectl", handler)
+}
+
+// wrapMutatingK8sTool applies a's per-call SubjectAccessReview check and p's
+// ReadOnly check for verb (see Policy.WrapMutatingVerb), in addition to
+// wrapTool's timeout/output/audit wrapping, for native k8s tools (k8s_apply,
+// k8s_delete, k8s_scale, k8s_exec) that mutate cluster state without going
+// through CheckCommand/CheckKubectl's command-line parsing — so
+// --policy-read-only covers them the same way it covers the kubectl tool.
+// Then mcpmetrics' unconditional call metrics, same as wrapTool.
+func wrapMutatingK8sTool(p *policy.Policy, a *mcpauth.Authorizer, verb, name string, handler policy.ToolHandler) policy.ToolHandler {
+	handler = mcpauth.WrapTool(a, name, handler)
+	if p != nil {
+		handler = p.WrapMutatingVerb(name, verb, handler)
+	}
+	return mcpmetrics.WrapTool(localUpstream, name, handler)
+}
+
+// registerK8sTools wires up the native, client-go backed Kubernetes tools
+// plus the list_contexts/current_context discovery tools, using a
+// contextmgr.Manager so calls can target any cluster the operator knows
+// about via the optional "context" argument. p and a, if non-nil, wrap every
+// registered tool with its timeout/output/audit behavior and per-call
+// authorization check, respectively; every tool is also unconditionally
+// wrapped with mcpmetrics' call counters and latency histogram. The mutating
+// tools (k8s_apply, k8s_delete, k8s_scale, k8s_exec) are also rejected by p
+// in ReadOnly mode, mirroring CheckKubectl's verb check.
+func registerK8sTools(server *mcp.Server, extraKubeconfigs []string, p *policy.Policy, a *mcpauth.Authorizer) {
+	manager := contextmgr.NewManager(extraKubeconfigs)
+	ts := k8s.NewToolset(manager)
+
+	listContextsTool := ts.NewListContextsTool()
+	server.AddTool(&listContextsTool, wrapTool(p, a, localUpstream, "list_contexts", ts.HandleListContexts))
+
+	currentContextTool := ts.NewCurrentContextTool()
+	server.AddTool(&currentContextTool, wrapTool(p, a, localUpstream, "current_context", ts.HandleCurrentContext))
+
+	getTool := ts.NewGetTool()
+	server.AddTool(&getTool, wrapTool(p, a, localUpstream, "k8s_get", ts.HandleGet))
+
+	listTool := ts.NewListTool()
+	server.AddTool(&listTool, wrapTool(p, a, localUpstream, "k8s_list", ts.HandleList))
+
+	applyTool := ts.NewApplyTool()
+	server.AddTool(&applyTool, wrapMutatingK8sTool(p, a, "apply", "k8s_apply", ts.HandleApply))
+
+	deleteTool := ts.NewDeleteTool()
+	server.AddTool(&deleteTool, wrapMutatingK8sTool(p, a, "delete", "k8s_delete", ts.HandleDelete))
+
+	scaleTool := ts.NewScaleTool()
+	server.AddTool(&scaleTool, wrapMutatingK8sTool(p, a, "scale", "k8s_scale", ts.HandleScale))
+
+	logsTool := ts.NewLogsTool()
+	server.AddTool(&logsTool, wrapTool(p, a, localUpstream, "k8s_logs", ts.HandleLogs))
+
+	execTool := ts.NewExecTool()
+	server.AddTool(&execTool, wrapMutatingK8sTool(p, a, "exec", "k8s_exec", ts.HandleExec))
+
+	waitTool := ts.NewWaitTool()
+	server.AddTool(&waitTool, wrapTool(p, a, localUpstream, "k8s_wait", ts.HandleWait))
+}
+
+// registerHelmTools wires up the Helm-backed install/upgrade/uninstall
+// tools. p and a, if non-nil, wrap every registered tool with its
+// timeout/output/audit behavior and per-call authorization check,
+// respectively.
+func registerHelmTools(server *mcp.Server, p *policy.Policy, a *mcpauth.Authorizer) {
+	helm := tools.NewHelmToolset()
+
+	installTool := helm.NewHelmInstallTool()
+	server.AddTool(&installTool, wrapTool(p, a, localUpstream, "helm_install", helm.HandleHelmInstall))
+
+	upgradeTool := helm.NewHelmUpgradeTool()
+	server.AddTool(&upgradeTool, wrapTool(p, a, localUpstream, "helm_upgrade", helm.HandleHelmUpgrade))
+
+	uninstallTool := helm.NewHelmUninstallTool()
+	server.AddTool(&uninstallTool, wrapTool(p, a, localUpstream, "helm_uninstall", helm.HandleHelmUninstall))
+
+	listTool := helm.NewHelmListTool()
+	server.AddTool(&listTool, wrapTool(p, a, localUpstream, "helm_list", helm.HandleHelmList))
+
+	repoAddTool := helm.NewHelmRepoAddTool()
+	server.AddTool(&repoAddTool, wrapTool(p, a, localUpstream, "helm_repo_add", helm.HandleHelmRepoAdd))
+}
+
+// registerPortForwardTools wires up the port_forward/list_port_forwards/
+// stop_port_forward tools against registry, creating one if registry is
+// nil. p and a, if non-nil, wrap every registered tool with its
+// timeout/output/audit behavior and per-call authorization check,
+// respectively.
+func registerPortForwardTools(server *mcp.Server, extraKubeconfigs []string, registry *tools.PortForwardRegistry, p *policy.Policy, a *mcpauth.Authorizer) {
+	if registry == nil {
+		registry = tools.NewPortForwardRegistry()
+	}
+	manager := contextmgr.NewManager(extraKubeconfigs)
+	pf := tools.NewPortForwardToolset(manager, registry)
+
+	portForwardTool := pf.NewPortForwardTool()
+	server.AddTool(&portForwardTool, wrapTool(p, a, localUpstream, "port_forward", pf.HandlePortForward))
+
+	listPortForwardsTool := pf.NewListPortForwardsTool()
+	server.AddTool(&listPortForwardsTool, wrapTool(p, a, localUpstream, "list_port_forwards", pf.HandleListPortForwards))
+
+	stopPortForwardTool := pf.NewStopPortForwardTool()
+	server.AddTool(&stopPortForwardTool, wrapTool(p, a, localUpstream, "stop_port_forward", pf.HandleStopPortForward))
+}
+
 // StartMCPServer starts the MCP server
 // Deprecated: Use InitProxies and NewServer manually for more control
 func StartMCPServer(ctx context.Context, opts ServerOptions) error {
-	proxies := InitProxies(ctx, opts.ArgoMCPCommand, opts.ArgoMCPURL, opts.K8sMCPCommand, opts.K8sMCPURL)
-	server := NewServer(ctx, proxies)
+	pfManager := contextmgr.NewManager(opts.ExtraKubeconfigs)
+	pfRegistry := tools.NewPortForwardRegistry()
+	proxies := InitProxies(ctx, opts.ArgoMCPCommand, opts.ArgoMCPURL, opts.ArgoMCPContext, opts.K8sMCPCommand, opts.K8sMCPURL, opts.K8sMCPContext, pfManager, pfRegistry, opts.ArgoPortForward)
+	server := NewServer(ctx, proxies, WithExtraKubeconfigs(opts.ExtraKubeconfigs...), WithPolicy(opts.Policy), WithAuthorizer(opts.Authorizer), WithPortForwardRegistry(pfRegistry))
 
 	if opts.HTTPAddr != "" {
 		log.Printf("Starting Kubegram MCP Server on HTTP %s...", opts.HTTPAddr)
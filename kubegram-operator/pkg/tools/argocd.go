@@ -4,14 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"reflect"
-	"strings"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"helm.sh/helm/v3/pkg/chart"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubegram/kubegram-operator/pkg/kube/contextmgr"
+	"github.com/kubegram/kubegram-operator/pkg/kube/wait"
 )
 
-// ArgoCDInstallerTool is a tool that installs Argo CD and optionally the Argo MCP server
+// ArgoCDInstallerTool is a tool that installs Argo CD and the Argo MCP server
+// sidecar via Helm, replacing the previous "kubectl apply -f -" approach so
+// upgrades, values overrides and `helm uninstall` all work as expected.
 type ArgoCDInstallerTool struct {
 }
 
@@ -19,96 +25,154 @@ type ArgoCDInstallerTool struct {
 func NewArgoCDInstallerTool() mcp.Tool {
 	return mcp.Tool{
 		Name:        "install_argo_mcp",
-		Description: "Installs the Argo MCP Server sidecar/deployment into the cluster. Requires an existing Argo CD installation or can be used to add the MCP capability.",
+		Description: "Installs Argo CD (if not already present) and the Argo MCP Server sidecar/deployment into the cluster via Helm.",
 		InputSchema: mustGenerateSchema(reflect.TypeOf(struct {
-			Namespace string `json:"namespace"`
-			MCPToken  string `json:"mcp_token"`
+			Namespace string                 `json:"namespace"`
+			MCPToken  string                 `json:"mcp_token"`
+			Values    map[string]interface{} `json:"values,omitempty"`
+			Context   string                 `json:"context,omitempty"`
 		}{})),
 	}
 }
 
-// HandleArgoCDInstall handles the execution of the install_argo_mcp tool
+// argoChartVersion pins the upstream Argo CD chart version installed by HandleArgoCDInstall.
+const argoChartVersion = "7.7.3"
+
+// HandleArgoCDInstall handles the execution of the install_argo_mcp tool. It
+// installs/upgrades the upstream argo/argo-cd chart, then installs/upgrades a
+// small in-memory chart for the argocd-mcp sidecar, using Helm's release
+// readiness wait instead of a bare "apply and hope".
 func HandleArgoCDInstall(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	var args struct {
-		Namespace string `json:"namespace"`
-		MCPToken  string `json:"mcp_token"`
+		Namespace string                 `json:"namespace"`
+		MCPToken  string                 `json:"mcp_token"`
+		Values    map[string]interface{} `json:"values,omitempty"`
+		Context   string                 `json:"context,omitempty"`
 	}
 
 	// Set defaults
 	args.Namespace = "argocd"
 
 	if err := json.Unmarshal(request.Params.Arguments, &args); err != nil {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Error parsing arguments: %v", err),
-				},
-			},
-			IsError: true,
-		}, nil
+		return errResult("Error parsing arguments: %v", err), nil
 	}
-
-	output := strings.Builder{}
-
 	if args.MCPToken == "" {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: "Error: mcp_token is required",
-				},
-			},
-			IsError: true,
-		}, nil
+		return errResult("Error: mcp_token is required"), nil
 	}
 
-	output.WriteString(fmt.Sprintf("\nInstalling Argo MCP Server into namespace %s...\n", args.Namespace))
+	helm := NewHelmToolset()
+	if args.Context != "" {
+		helm.settings.KubeContext = args.Context
+	}
+
+	argoInstall, err := helm.installOrUpgrade(ctx, helmReleaseInput{
+		Chart:       "argo/argo-cd",
+		Version:     argoChartVersion,
+		Namespace:   args.Namespace,
+		ReleaseName: "argocd",
+		Values:      args.Values,
+		Wait:        true,
+		TimeoutSecs: 300,
+	})
+	if err != nil {
+		return errResult("Failed to install Argo CD: %v", err), nil
+	}
 
-	// Determine Argo CD URL (internal service DNS)
-	// Assuming standard Helm install names: argocd-server
 	argoURL := fmt.Sprintf("http://argocd-server.%s.svc.cluster.local", args.Namespace)
+	mcpChart := newArgoMCPChart()
+	mcpValues := map[string]interface{}{
+		"namespace": args.Namespace,
+		"argoURL":   argoURL,
+		"token":     args.MCPToken,
+	}
 
-	manifest := fmt.Sprintf(argoMCPManifestTemplate, args.Namespace, args.Namespace, argoURL, args.MCPToken, args.Namespace)
-
-	// Apply via kubectl
-	cmd := exec.CommandContext(ctx, "kubectl", "apply", "-f", "-")
-	cmd.Stdin = strings.NewReader(manifest)
-	if out, err := cmd.CombinedOutput(); err != nil {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Failed to apply Argo MCP manifest: %s\nOutput: %s", err, string(out)),
-				},
-			},
-			IsError: true,
-		}, nil
+	mcpInstall, err := helm.installOrUpgradeChart(ctx, mcpChart, args.Namespace, "argocd-mcp", mcpValues, true, 120)
+	if err != nil {
+		return errResult("Failed to install Argo MCP sidecar: %v", err), nil
 	}
-	output.WriteString("Argo MCP Server installed successfully.\n")
-	output.WriteString(fmt.Sprintf("MCP Server URL (Internal): http://argocd-mcp.%s.svc.cluster.local:8080/sse\n", args.Namespace))
-	output.WriteString("\nNOTE: You may need to update the Kubegram Operator configuration to point to this new MCP server if it's not the default one.")
+
+	readiness := waitForMCPSidecarReady(ctx, args.Context, args.Namespace)
 
 	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			&mcp.TextContent{
-				Text: output.String(),
-			},
-		},
+		Content: []mcp.Content{&mcp.TextContent{
+			Text: fmt.Sprintf("Argo CD release %q: %s\nArgo MCP release %q: %s\nMCP Server URL (Internal): http://argocd-mcp.%s.svc.cluster.local:8080/sse\n%s",
+				"argocd", argoInstall, "argocd-mcp", mcpInstall, args.Namespace, readiness),
+		}},
 	}, nil
 }
 
-// argoMCPManifestTemplate is the YAML manifest for Argo MCP Server
-// We use Sprintf placeholders: %s for namespace (x4), %s for ARGOCD_API_URL, %s for ARGOCD_TOKEN
-const argoMCPManifestTemplate = `
+// waitForMCPSidecarReady blocks until the argocd-mcp Deployment and Service
+// are ready (or two minutes elapse), returning a short human-readable
+// summary of what it found. Helm's own Wait already blocks the install on
+// rollout completion, but it cannot report per-object status back to the
+// caller, so we additionally run the native readiness check here.
+func waitForMCPSidecarReady(ctx context.Context, kubeContext, namespace string) string {
+	client, err := contextmgr.NewManager(nil).Resolve(kubeContext)
+	if err != nil {
+		return fmt.Sprintf("Readiness check skipped: %v", err)
+	}
+
+	objects := []*unstructured.Unstructured{
+		{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "argocd-mcp", "namespace": namespace},
+		}},
+		{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata":   map[string]interface{}{"name": "argocd-mcp", "namespace": namespace},
+		}},
+	}
+
+	statuses, err := wait.WaitForReady(ctx, client, objects, 2*time.Minute)
+	if err != nil {
+		return fmt.Sprintf("Readiness check failed: %v", err)
+	}
+
+	result := "Readiness:"
+	for _, s := range statuses {
+		result += fmt.Sprintf("\n  %s/%s: ready=%t (%s)", s.Kind, s.Name, s.Ready, s.Message)
+	}
+	return result
+}
+
+// newArgoMCPChart builds the small in-memory chart for the argocd-mcp sidecar,
+// keeping the same Deployment/Service/ServiceAccount shape as the previous
+// hard-coded manifest but as Helm templates so it gets idempotent
+// install/upgrade/uninstall semantics.
+func newArgoMCPChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{
+			Name:       "argocd-mcp",
+			Version:    "0.1.0",
+			APIVersion: chart.APIVersionV2,
+		},
+		Templates: []*chart.File{
+			{Name: "templates/argocd-mcp.yaml", Data: []byte(argoMCPChartTemplate)},
+		},
+		Values: map[string]interface{}{
+			"namespace": "argocd",
+			"argoURL":   "",
+			"token":     "",
+		},
+	}
+}
+
+// argoMCPChartTemplate is the Helm template for the argocd-mcp sidecar. It
+// keeps the same shape as the manifest formerly applied directly via kubectl.
+const argoMCPChartTemplate = `
 apiVersion: v1
 kind: ServiceAccount
 metadata:
   name: argocd-mcp
-  namespace: %s
+  namespace: {{ .Values.namespace }}
 ---
 apiVersion: apps/v1
 kind: Deployment
 metadata:
   name: argocd-mcp
-  namespace: %s
+  namespace: {{ .Values.namespace }}
   labels:
     app: argocd-mcp
 spec:
@@ -128,9 +192,9 @@ spec:
           imagePullPolicy: IfNotPresent
           env:
             - name: ARGOCD_API_URL
-              value: "%s"
+              value: {{ .Values.argoURL | quote }}
             - name: ARGOCD_TOKEN
-              value: "%s"
+              value: {{ .Values.token | quote }}
             - name: ARGOCD_VERIFY_SSL
               value: "false"
           ports:
@@ -155,7 +219,7 @@ apiVersion: v1
 kind: Service
 metadata:
   name: argocd-mcp
-  namespace: %s
+  namespace: {{ .Values.namespace }}
 spec:
   type: ClusterIP
   ports:
@@ -0,0 +1,369 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// HelmTool wraps the Helm SDK to provide install/upgrade/uninstall/list/repo-add
+// MCP tools, replacing the "kubectl apply -f -" semantics HandleArgoCDInstall previously used.
+type HelmTool struct {
+	settings *cli.EnvSettings
+}
+
+// NewHelmToolset creates a HelmTool using Helm's default environment settings
+// (respecting HELM_NAMESPACE, KUBECONFIG, etc. from the operator's environment).
+func NewHelmToolset() *HelmTool {
+	return &HelmTool{settings: cli.New()}
+}
+
+func (h *HelmTool) configuration(namespace string) (*action.Configuration, error) {
+	cfg := new(action.Configuration)
+	if err := cfg.Init(h.settings.RESTClientGetter(), namespace, "secret", debugLog); err != nil {
+		return nil, fmt.Errorf("failed to initialize helm configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+func debugLog(format string, v ...interface{}) {}
+
+// installOrUpgrade locates and loads in.Chart from its repo/OCI reference,
+// then installs or upgrades the named release. It is used by
+// HandleArgoCDInstall to bring in the upstream argo/argo-cd chart.
+func (h *HelmTool) installOrUpgrade(ctx context.Context, in helmReleaseInput) (string, error) {
+	cfg, err := h.configuration(in.Namespace)
+	if err != nil {
+		return "", err
+	}
+
+	installClient := action.NewInstall(cfg)
+	installClient.ChartPathOptions.Version = in.Version
+	chartPath, err := installClient.ChartPathOptions.LocateChart(in.Chart, h.settings)
+	if err != nil {
+		return "", fmt.Errorf("failed to locate chart %s: %w", in.Chart, err)
+	}
+	chrt, err := loader.Load(chartPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to load chart %s: %w", in.Chart, err)
+	}
+
+	return h.installOrUpgradeChart(ctx, chrt, in.Namespace, in.ReleaseName, in.Values, in.Wait, in.TimeoutSecs)
+}
+
+// installOrUpgradeChart installs the release if it does not yet exist,
+// otherwise upgrades it in place, returning a short human-readable status.
+func (h *HelmTool) installOrUpgradeChart(ctx context.Context, chrt *chart.Chart, namespace, releaseName string, values map[string]interface{}, wait bool, timeoutSecs int) (string, error) {
+	cfg, err := h.configuration(namespace)
+	if err != nil {
+		return "", err
+	}
+	timeout := helmReleaseInput{TimeoutSecs: timeoutSecs}.timeout()
+
+	history := action.NewHistory(cfg)
+	history.Max = 1
+	if _, err := history.Run(releaseName); err != nil {
+		// No prior release: install.
+		install := action.NewInstall(cfg)
+		install.Namespace = namespace
+		install.ReleaseName = releaseName
+		install.CreateNamespace = true
+		install.Wait = wait
+		install.Timeout = timeout
+
+		rel, err := install.RunWithContext(ctx, chrt, values)
+		if err != nil {
+			return "", fmt.Errorf("install failed: %w", err)
+		}
+		return fmt.Sprintf("installed (status: %s)", rel.Info.Status), nil
+	}
+
+	upgrade := action.NewUpgrade(cfg)
+	upgrade.Namespace = namespace
+	upgrade.Wait = wait
+	upgrade.Timeout = timeout
+
+	rel, err := upgrade.RunWithContext(ctx, releaseName, chrt, values)
+	if err != nil {
+		return "", fmt.Errorf("upgrade failed: %w", err)
+	}
+	return fmt.Sprintf("upgraded to revision %d (status: %s)", rel.Version, rel.Info.Status), nil
+}
+
+// helmReleaseInput describes a Helm chart and release to act on.
+type helmReleaseInput struct {
+	Chart       string                 `json:"chart"`
+	Version     string                 `json:"version,omitempty"`
+	Namespace   string                 `json:"namespace"`
+	ReleaseName string                 `json:"release_name"`
+	Values      map[string]interface{} `json:"values,omitempty"`
+	Wait        bool                   `json:"wait,omitempty"`
+	TimeoutSecs int                    `json:"timeout_seconds,omitempty"`
+}
+
+func (in helmReleaseInput) timeout() time.Duration {
+	if in.TimeoutSecs <= 0 {
+		return 5 * time.Minute
+	}
+	return time.Duration(in.TimeoutSecs) * time.Second
+}
+
+// NewHelmInstallTool creates the helm_install MCP tool.
+func (h *HelmTool) NewHelmInstallTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "helm_install",
+		Description: "Install a Helm chart (repo/name or OCI reference) as a new release, merging an optional values map onto the chart defaults.",
+		InputSchema: mustGenerateSchema(reflect.TypeOf(helmReleaseInput{})),
+	}
+}
+
+// HandleHelmInstall executes the helm_install tool.
+func (h *HelmTool) HandleHelmInstall(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var in helmReleaseInput
+	if err := json.Unmarshal(request.Params.Arguments, &in); err != nil {
+		return errResult("failed to unmarshal arguments: %v", err), nil
+	}
+	if in.Chart == "" || in.ReleaseName == "" {
+		return errResult("chart and release_name are required"), nil
+	}
+
+	cfg, err := h.configuration(in.Namespace)
+	if err != nil {
+		return errResult("%v", err), nil
+	}
+
+	client := action.NewInstall(cfg)
+	client.Namespace = in.Namespace
+	client.ReleaseName = in.ReleaseName
+	client.CreateNamespace = true
+	client.ChartPathOptions.Version = in.Version
+	client.Wait = in.Wait
+	client.Timeout = in.timeout()
+
+	chartPath, err := client.ChartPathOptions.LocateChart(in.Chart, h.settings)
+	if err != nil {
+		return errResult("failed to locate chart %s: %v", in.Chart, err), nil
+	}
+	chrt, err := loader.Load(chartPath)
+	if err != nil {
+		return errResult("failed to load chart %s: %v", in.Chart, err), nil
+	}
+
+	rel, err := client.RunWithContext(ctx, chrt, in.Values)
+	if err != nil {
+		return errResult("helm install failed: %v", err), nil
+	}
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{
+		Text: fmt.Sprintf("Installed release %q (chart %s, version %s) into namespace %s, status: %s",
+			rel.Name, rel.Chart.Metadata.Name, rel.Chart.Metadata.Version, rel.Namespace, rel.Info.Status),
+	}}}, nil
+}
+
+// NewHelmUpgradeTool creates the helm_upgrade MCP tool.
+func (h *HelmTool) NewHelmUpgradeTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "helm_upgrade",
+		Description: "Upgrade an existing Helm release to a new chart version and/or values, installing it first if it does not yet exist.",
+		InputSchema: mustGenerateSchema(reflect.TypeOf(helmReleaseInput{})),
+	}
+}
+
+// HandleHelmUpgrade executes the helm_upgrade tool.
+func (h *HelmTool) HandleHelmUpgrade(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var in helmReleaseInput
+	if err := json.Unmarshal(request.Params.Arguments, &in); err != nil {
+		return errResult("failed to unmarshal arguments: %v", err), nil
+	}
+	if in.Chart == "" || in.ReleaseName == "" {
+		return errResult("chart and release_name are required"), nil
+	}
+
+	cfg, err := h.configuration(in.Namespace)
+	if err != nil {
+		return errResult("%v", err), nil
+	}
+
+	client := action.NewUpgrade(cfg)
+	client.ChartPathOptions.Version = in.Version
+
+	chartPath, err := client.ChartPathOptions.LocateChart(in.Chart, h.settings)
+	if err != nil {
+		return errResult("failed to locate chart %s: %v", in.Chart, err), nil
+	}
+	chrt, err := loader.Load(chartPath)
+	if err != nil {
+		return errResult("failed to load chart %s: %v", in.Chart, err), nil
+	}
+
+	// action.Upgrade on its own fails for a release that does not exist yet,
+	// so go through installOrUpgradeChart to install it in that case.
+	status, err := h.installOrUpgradeChart(ctx, chrt, in.Namespace, in.ReleaseName, in.Values, in.Wait, in.TimeoutSecs)
+	if err != nil {
+		return errResult("helm upgrade failed: %v", err), nil
+	}
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{
+		Text: fmt.Sprintf("Release %q (chart version %s): %s", in.ReleaseName, chrt.Metadata.Version, status),
+	}}}, nil
+}
+
+// helmReleaseRef identifies a release to uninstall.
+type helmReleaseRef struct {
+	Namespace   string `json:"namespace"`
+	ReleaseName string `json:"release_name"`
+}
+
+// NewHelmUninstallTool creates the helm_uninstall MCP tool.
+func (h *HelmTool) NewHelmUninstallTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "helm_uninstall",
+		Description: "Uninstall a Helm release, removing all resources it owns.",
+		InputSchema: mustGenerateSchema(reflect.TypeOf(helmReleaseRef{})),
+	}
+}
+
+// HandleHelmUninstall executes the helm_uninstall tool.
+func (h *HelmTool) HandleHelmUninstall(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var in helmReleaseRef
+	if err := json.Unmarshal(request.Params.Arguments, &in); err != nil {
+		return errResult("failed to unmarshal arguments: %v", err), nil
+	}
+	if in.ReleaseName == "" {
+		return errResult("release_name is required"), nil
+	}
+
+	cfg, err := h.configuration(in.Namespace)
+	if err != nil {
+		return errResult("%v", err), nil
+	}
+
+	client := action.NewUninstall(cfg)
+	resp, err := client.Run(in.ReleaseName)
+	if err != nil {
+		return errResult("helm uninstall failed: %v", err), nil
+	}
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{
+		Text: fmt.Sprintf("Uninstalled release %q: %s", in.ReleaseName, resp.Info),
+	}}}, nil
+}
+
+// helmListInput scopes the helm_list tool to a namespace.
+type helmListInput struct {
+	Namespace     string `json:"namespace,omitempty"`
+	AllNamespaces bool   `json:"all_namespaces,omitempty"`
+}
+
+// NewHelmListTool creates the helm_list MCP tool.
+func (h *HelmTool) NewHelmListTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "helm_list",
+		Description: "List installed Helm releases in a namespace, or across all namespaces.",
+		InputSchema: mustGenerateSchema(reflect.TypeOf(helmListInput{})),
+	}
+}
+
+// HandleHelmList executes the helm_list tool.
+func (h *HelmTool) HandleHelmList(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var in helmListInput
+	if err := json.Unmarshal(request.Params.Arguments, &in); err != nil {
+		return errResult("failed to unmarshal arguments: %v", err), nil
+	}
+
+	cfg, err := h.configuration(in.Namespace)
+	if err != nil {
+		return errResult("%v", err), nil
+	}
+
+	client := action.NewList(cfg)
+	client.AllNamespaces = in.AllNamespaces
+
+	releases, err := client.Run()
+	if err != nil {
+		return errResult("helm list failed: %v", err), nil
+	}
+
+	summaries := make([]string, 0, len(releases))
+	for _, rel := range releases {
+		summaries = append(summaries, fmt.Sprintf("%s/%s\tchart=%s\tversion=%d\tstatus=%s",
+			rel.Namespace, rel.Name, rel.Chart.Metadata.Name, rel.Version, rel.Info.Status))
+	}
+
+	text := "No releases found."
+	if len(summaries) > 0 {
+		text = summaries[0]
+		for _, s := range summaries[1:] {
+			text += "\n" + s
+		}
+	}
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil
+}
+
+// helmRepoAddInput describes a chart repository to register.
+type helmRepoAddInput struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// NewHelmRepoAddTool creates the helm_repo_add MCP tool.
+func (h *HelmTool) NewHelmRepoAddTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "helm_repo_add",
+		Description: "Add (or update) a Helm chart repository so its charts can be referenced by name.",
+		InputSchema: mustGenerateSchema(reflect.TypeOf(helmRepoAddInput{})),
+	}
+}
+
+// HandleHelmRepoAdd executes the helm_repo_add tool.
+func (h *HelmTool) HandleHelmRepoAdd(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var in helmRepoAddInput
+	if err := json.Unmarshal(request.Params.Arguments, &in); err != nil {
+		return errResult("failed to unmarshal arguments: %v", err), nil
+	}
+	if in.Name == "" || in.URL == "" {
+		return errResult("name and url are required"), nil
+	}
+
+	repoFile := h.settings.RepositoryConfig
+	file, err := repo.LoadFile(repoFile)
+	if err != nil {
+		file = repo.NewFile()
+	}
+
+	entry := &repo.Entry{Name: in.Name, URL: in.URL}
+	chartRepo, err := repo.NewChartRepository(entry, getter.All(h.settings))
+	if err != nil {
+		return errResult("failed to construct repository %s: %v", in.Name, err), nil
+	}
+	if _, err := chartRepo.DownloadIndexFile(); err != nil {
+		return errResult("failed to reach repository %s at %s: %v", in.Name, in.URL, err), nil
+	}
+
+	file.Update(entry)
+	if err := file.WriteFile(repoFile, 0644); err != nil {
+		return errResult("failed to persist repository config: %v", err), nil
+	}
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{
+		Text: fmt.Sprintf("Added repository %q -> %s", in.Name, in.URL),
+	}}}, nil
+}
+
+func errResult(format string, args ...interface{}) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf(format, args...)}},
+	}
+}
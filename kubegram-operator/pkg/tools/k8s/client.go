@@ -0,0 +1,16 @@
+// Package k8s provides native, client-go backed MCP tools for interacting with
+// a Kubernetes cluster, replacing the exec-based kubectl wrapper in pkg/tools.
+package k8s
+
+import "github.com/kubegram/kubegram-operator/pkg/kube/contextmgr"
+
+// Client is the resolved typed/dynamic client pair tools operate against.
+// Resolution (including multi-cluster/kubecontext support) is delegated to
+// contextmgr.Manager.
+type Client = contextmgr.Client
+
+// NewClient resolves the default Client: in-cluster config when running
+// inside a Pod, otherwise the ambient kubeconfig's current context.
+func NewClient() (*Client, error) {
+	return contextmgr.NewManager(nil).Resolve("")
+}
@@ -0,0 +1,38 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// execInPod runs command inside the given Pod/container using the SPDY
+// executor, returning the collected stdout and stderr separately.
+func execInPod(ctx context.Context, client *Client, namespace, pod, container string, command []string) (stdout, stderr string, err error) {
+	req := client.Typed.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(pod).
+		Namespace(namespace).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(client.Config, "POST", req.URL())
+	if err != nil {
+		return "", "", err
+	}
+
+	var outBuf, errBuf bytes.Buffer
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: &outBuf,
+		Stderr: &errBuf,
+	})
+	return outBuf.String(), errBuf.String(), err
+}
@@ -0,0 +1,511 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/kubegram/kubegram-operator/pkg/kube/contextmgr"
+	"github.com/kubegram/kubegram-operator/pkg/kube/wait"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+func mustGenerateSchema(t reflect.Type) *jsonschema.Schema {
+	s, err := jsonschema.ForType(t, &jsonschema.ForOptions{})
+	if err != nil {
+		panic(fmt.Sprintf("failed to generate schema: %v", err))
+	}
+	return s
+}
+
+// gvkSelector identifies a single resource or a set of resources to list.
+type gvkSelector struct {
+	Group         string `json:"group"`
+	Version       string `json:"version"`
+	Kind          string `json:"kind"`
+	Namespace     string `json:"namespace,omitempty"`
+	Name          string `json:"name,omitempty"`
+	LabelSelector string `json:"label_selector,omitempty"`
+	FieldSelector string `json:"field_selector,omitempty"`
+	// Context selects a kubeconfig context to run against; if empty, the
+	// manager's default (in-cluster, or the kubeconfig's current-context) is used.
+	Context string `json:"context,omitempty"`
+}
+
+func (s gvkSelector) gvk() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: s.Group, Version: s.Version, Kind: s.Kind}
+}
+
+func errorResult(format string, args ...interface{}) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf(format, args...)}},
+	}
+}
+
+func jsonResult(v interface{}) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(data)}},
+	}, nil
+}
+
+// Toolset registers the native Kubernetes MCP tools. Each call resolves its
+// Client through a contextmgr.Manager, so callers can target any context
+// known to the operator's kubeconfig(s) via the optional "context" field.
+// NewServer constructs one of these and registers its tools by default,
+// preferring them over the exec-based kubectl tool.
+type Toolset struct {
+	manager *contextmgr.Manager
+}
+
+// NewToolset creates a Toolset backed by the given contextmgr.Manager.
+func NewToolset(manager *contextmgr.Manager) *Toolset {
+	return &Toolset{manager: manager}
+}
+
+// NewListContextsTool creates the list_contexts discovery tool.
+func (ts *Toolset) NewListContextsTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "list_contexts",
+		Description: "List every kubeconfig context known to the operator, for use as the optional \"context\" argument on other tools.",
+		InputSchema: mustGenerateSchema(reflect.TypeOf(struct{}{})),
+	}
+}
+
+// HandleListContexts executes the list_contexts tool.
+func (ts *Toolset) HandleListContexts(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	names, err := ts.manager.Contexts()
+	if err != nil {
+		return errorResult("%v", err), nil
+	}
+	sort.Strings(names)
+	return jsonResult(names)
+}
+
+// NewCurrentContextTool creates the current_context discovery tool.
+func (ts *Toolset) NewCurrentContextTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "current_context",
+		Description: "Report the kubeconfig context that tools use by default when no \"context\" argument is given.",
+		InputSchema: mustGenerateSchema(reflect.TypeOf(struct{}{})),
+	}
+}
+
+// HandleCurrentContext executes the current_context tool.
+func (ts *Toolset) HandleCurrentContext(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := ts.manager.CurrentContext()
+	if err != nil {
+		return errorResult("%v", err), nil
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: name}}}, nil
+}
+
+// NewGetTool creates the k8s_get tool, which fetches a single resource by GVK/namespace/name.
+func (ts *Toolset) NewGetTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "k8s_get",
+		Description: "Get a single Kubernetes resource identified by group/version/kind, namespace and name.",
+		InputSchema: mustGenerateSchema(reflect.TypeOf(gvkSelector{})),
+	}
+}
+
+// HandleGet executes the k8s_get tool.
+func (ts *Toolset) HandleGet(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var sel gvkSelector
+	if err := json.Unmarshal(request.Params.Arguments, &sel); err != nil {
+		return errorResult("failed to unmarshal arguments: %v", err), nil
+	}
+	if sel.Name == "" {
+		return errorResult("name is required"), nil
+	}
+
+	client, err := ts.manager.Resolve(sel.Context)
+	if err != nil {
+		return errorResult("%v", err), nil
+	}
+
+	gvr, namespaced, err := client.ResourceFor(sel.gvk())
+	if err != nil {
+		return errorResult("%v", err), nil
+	}
+
+	var obj *unstructured.Unstructured
+	if namespaced {
+		obj, err = client.Dynamic.Resource(gvr).Namespace(sel.Namespace).Get(ctx, sel.Name, metav1.GetOptions{})
+	} else {
+		obj, err = client.Dynamic.Resource(gvr).Get(ctx, sel.Name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return errorResult("failed to get %s/%s: %v", sel.Kind, sel.Name, err), nil
+	}
+
+	return jsonResult(obj.Object)
+}
+
+// NewListTool creates the k8s_list tool, which lists resources matching a GVK and optional selectors.
+func (ts *Toolset) NewListTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "k8s_list",
+		Description: "List Kubernetes resources of a given group/version/kind, optionally filtered by namespace, label selector or field selector.",
+		InputSchema: mustGenerateSchema(reflect.TypeOf(gvkSelector{})),
+	}
+}
+
+// HandleList executes the k8s_list tool.
+func (ts *Toolset) HandleList(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var sel gvkSelector
+	if err := json.Unmarshal(request.Params.Arguments, &sel); err != nil {
+		return errorResult("failed to unmarshal arguments: %v", err), nil
+	}
+
+	client, err := ts.manager.Resolve(sel.Context)
+	if err != nil {
+		return errorResult("%v", err), nil
+	}
+
+	gvr, namespaced, err := client.ResourceFor(sel.gvk())
+	if err != nil {
+		return errorResult("%v", err), nil
+	}
+
+	opts := metav1.ListOptions{LabelSelector: sel.LabelSelector, FieldSelector: sel.FieldSelector}
+
+	var list *unstructured.UnstructuredList
+	if namespaced {
+		list, err = client.Dynamic.Resource(gvr).Namespace(sel.Namespace).List(ctx, opts)
+	} else {
+		list, err = client.Dynamic.Resource(gvr).List(ctx, opts)
+	}
+	if err != nil {
+		return errorResult("failed to list %s: %v", sel.Kind, err), nil
+	}
+
+	return jsonResult(list.Object)
+}
+
+// applyInput is the input schema for the k8s_apply tool.
+type applyInput struct {
+	Namespace string `json:"namespace,omitempty"`
+	Manifest  string `json:"manifest"`
+	Context   string `json:"context,omitempty"`
+}
+
+// NewApplyTool creates the k8s_apply tool, which server-side applies a YAML or JSON manifest.
+func (ts *Toolset) NewApplyTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "k8s_apply",
+		Description: "Apply a single YAML or JSON manifest via server-side apply, creating or updating the resource.",
+		InputSchema: mustGenerateSchema(reflect.TypeOf(applyInput{})),
+	}
+}
+
+// HandleApply executes the k8s_apply tool.
+func (ts *Toolset) HandleApply(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var in applyInput
+	if err := json.Unmarshal(request.Params.Arguments, &in); err != nil {
+		return errorResult("failed to unmarshal arguments: %v", err), nil
+	}
+	if in.Manifest == "" {
+		return errorResult("manifest cannot be empty"), nil
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal([]byte(in.Manifest), &obj.Object); err != nil {
+		return errorResult("failed to parse manifest: %v", err), nil
+	}
+
+	namespace := in.Namespace
+	if namespace == "" {
+		namespace = obj.GetNamespace()
+	}
+
+	client, err := ts.manager.Resolve(in.Context)
+	if err != nil {
+		return errorResult("%v", err), nil
+	}
+
+	gvr, namespaced, err := client.ResourceFor(obj.GroupVersionKind())
+	if err != nil {
+		return errorResult("%v", err), nil
+	}
+
+	applyOpts := metav1.ApplyOptions{FieldManager: "kubegram-operator", Force: true}.ToPatchOptions()
+
+	var result *unstructured.Unstructured
+	if namespaced {
+		result, err = client.Dynamic.Resource(gvr).Namespace(namespace).Patch(ctx, obj.GetName(), types.ApplyPatchType, []byte(in.Manifest), applyOpts)
+	} else {
+		result, err = client.Dynamic.Resource(gvr).Patch(ctx, obj.GetName(), types.ApplyPatchType, []byte(in.Manifest), applyOpts)
+	}
+	if err != nil {
+		return errorResult("failed to apply %s/%s: %v", obj.GetKind(), obj.GetName(), err), nil
+	}
+
+	return jsonResult(result.Object)
+}
+
+// NewDeleteTool creates the k8s_delete tool, which deletes a single resource by GVK/namespace/name.
+func (ts *Toolset) NewDeleteTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "k8s_delete",
+		Description: "Delete a single Kubernetes resource identified by group/version/kind, namespace and name.",
+		InputSchema: mustGenerateSchema(reflect.TypeOf(gvkSelector{})),
+	}
+}
+
+// HandleDelete executes the k8s_delete tool.
+func (ts *Toolset) HandleDelete(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var sel gvkSelector
+	if err := json.Unmarshal(request.Params.Arguments, &sel); err != nil {
+		return errorResult("failed to unmarshal arguments: %v", err), nil
+	}
+	if sel.Name == "" {
+		return errorResult("name is required"), nil
+	}
+
+	client, err := ts.manager.Resolve(sel.Context)
+	if err != nil {
+		return errorResult("%v", err), nil
+	}
+
+	gvr, namespaced, err := client.ResourceFor(sel.gvk())
+	if err != nil {
+		return errorResult("%v", err), nil
+	}
+
+	if namespaced {
+		err = client.Dynamic.Resource(gvr).Namespace(sel.Namespace).Delete(ctx, sel.Name, metav1.DeleteOptions{})
+	} else {
+		err = client.Dynamic.Resource(gvr).Delete(ctx, sel.Name, metav1.DeleteOptions{})
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errorResult("failed to delete %s/%s: %v", sel.Kind, sel.Name, err), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("deleted %s/%s", sel.Kind, sel.Name)}},
+	}, nil
+}
+
+// waitInput is the input schema for the k8s_wait tool.
+type waitInput struct {
+	gvkSelector
+	Condition   string `json:"condition,omitempty"`
+	TimeoutSecs int    `json:"timeout_seconds,omitempty"`
+}
+
+// NewWaitTool creates the k8s_wait tool, which blocks until a resource
+// reaches readiness or an explicit named status condition turns True.
+func (ts *Toolset) NewWaitTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "k8s_wait",
+		Description: "Wait for a Kubernetes resource to become ready (Deployment/StatefulSet/DaemonSet rollout, Pod Ready, Service endpoints, PVC Bound, Job Complete, CRD Established), or, if \"condition\" is set, for that named status condition to turn True.",
+		InputSchema: mustGenerateSchema(reflect.TypeOf(waitInput{})),
+	}
+}
+
+// HandleWait executes the k8s_wait tool.
+func (ts *Toolset) HandleWait(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var in waitInput
+	if err := json.Unmarshal(request.Params.Arguments, &in); err != nil {
+		return errorResult("failed to unmarshal arguments: %v", err), nil
+	}
+	if in.Name == "" {
+		return errorResult("name is required"), nil
+	}
+
+	client, err := ts.manager.Resolve(in.Context)
+	if err != nil {
+		return errorResult("%v", err), nil
+	}
+
+	timeout := 2 * time.Minute
+	if in.TimeoutSecs > 0 {
+		timeout = time.Duration(in.TimeoutSecs) * time.Second
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": in.gvk().GroupVersion().String(),
+		"kind":       in.Kind,
+		"metadata":   map[string]interface{}{"name": in.Name, "namespace": in.Namespace},
+	}}
+
+	var status wait.Status
+	if in.Condition != "" {
+		ready, msg, err := wait.WaitForNamedCondition(ctx, client, obj, in.Condition, timeout)
+		if err != nil {
+			return errorResult("%v", err), nil
+		}
+		status = wait.Status{Kind: in.Kind, Name: in.Name, Namespace: in.Namespace, Ready: ready, Message: msg}
+	} else {
+		statuses, err := wait.WaitForReady(ctx, client, []*unstructured.Unstructured{obj}, timeout)
+		if err != nil {
+			return errorResult("%v", err), nil
+		}
+		status = statuses[0]
+	}
+
+	return jsonResult(status)
+}
+
+// scaleInput is the input schema for the k8s_scale tool.
+type scaleInput struct {
+	gvkSelector
+	Replicas int32 `json:"replicas"`
+}
+
+// NewScaleTool creates the k8s_scale tool, which patches the replicas of a scalable resource.
+func (ts *Toolset) NewScaleTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "k8s_scale",
+		Description: "Scale a Deployment, StatefulSet or ReplicaSet to the given number of replicas.",
+		InputSchema: mustGenerateSchema(reflect.TypeOf(scaleInput{})),
+	}
+}
+
+// HandleScale executes the k8s_scale tool.
+func (ts *Toolset) HandleScale(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var in scaleInput
+	if err := json.Unmarshal(request.Params.Arguments, &in); err != nil {
+		return errorResult("failed to unmarshal arguments: %v", err), nil
+	}
+	if in.Name == "" {
+		return errorResult("name is required"), nil
+	}
+
+	client, err := ts.manager.Resolve(in.Context)
+	if err != nil {
+		return errorResult("%v", err), nil
+	}
+
+	gvr, namespaced, err := client.ResourceFor(in.gvk())
+	if err != nil {
+		return errorResult("%v", err), nil
+	}
+	if !namespaced {
+		return errorResult("%s is not a namespaced, scalable resource", in.Kind), nil
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"replicas":%d}}`, in.Replicas)
+	result, err := client.Dynamic.Resource(gvr).Namespace(in.Namespace).Patch(ctx, in.Name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return errorResult("failed to scale %s/%s: %v", in.Kind, in.Name, err), nil
+	}
+
+	return jsonResult(result.Object)
+}
+
+// logsInput is the input schema for the k8s_logs tool.
+type logsInput struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container,omitempty"`
+	TailLines int64  `json:"tail_lines,omitempty"`
+	Previous  bool   `json:"previous,omitempty"`
+	Context   string `json:"context,omitempty"`
+}
+
+// NewLogsTool creates the k8s_logs tool, which streams a Pod's logs.
+func (ts *Toolset) NewLogsTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "k8s_logs",
+		Description: "Fetch logs for a Pod (optionally a specific container), with an optional tail line limit.",
+		InputSchema: mustGenerateSchema(reflect.TypeOf(logsInput{})),
+	}
+}
+
+// HandleLogs executes the k8s_logs tool.
+func (ts *Toolset) HandleLogs(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var in logsInput
+	if err := json.Unmarshal(request.Params.Arguments, &in); err != nil {
+		return errorResult("failed to unmarshal arguments: %v", err), nil
+	}
+	if in.Pod == "" {
+		return errorResult("pod is required"), nil
+	}
+
+	client, err := ts.manager.Resolve(in.Context)
+	if err != nil {
+		return errorResult("%v", err), nil
+	}
+
+	opts := &corev1.PodLogOptions{Container: in.Container, Previous: in.Previous}
+	if in.TailLines > 0 {
+		opts.TailLines = &in.TailLines
+	}
+
+	req := client.Typed.CoreV1().Pods(in.Namespace).GetLogs(in.Pod, opts)
+	stream, err := req.Stream(ctx)
+	if err != nil {
+		return errorResult("failed to stream logs for %s/%s: %v", in.Namespace, in.Pod, err), nil
+	}
+	defer stream.Close()
+
+	buf := make([]byte, 0, 4096)
+	chunk := make([]byte, 4096)
+	for {
+		n, readErr := stream.Read(chunk)
+		buf = append(buf, chunk[:n]...)
+		if readErr != nil {
+			break
+		}
+	}
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(buf)}}}, nil
+}
+
+// execInput is the input schema for the k8s_exec tool.
+type execInput struct {
+	Namespace string   `json:"namespace"`
+	Pod       string   `json:"pod"`
+	Container string   `json:"container,omitempty"`
+	Command   []string `json:"command"`
+	Context   string   `json:"context,omitempty"`
+}
+
+// NewExecTool creates the k8s_exec tool, which runs a command inside a container.
+func (ts *Toolset) NewExecTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "k8s_exec",
+		Description: "Execute a command inside a running container and return its combined stdout/stderr.",
+		InputSchema: mustGenerateSchema(reflect.TypeOf(execInput{})),
+	}
+}
+
+// HandleExec executes the k8s_exec tool.
+func (ts *Toolset) HandleExec(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var in execInput
+	if err := json.Unmarshal(request.Params.Arguments, &in); err != nil {
+		return errorResult("failed to unmarshal arguments: %v", err), nil
+	}
+	if in.Pod == "" || len(in.Command) == 0 {
+		return errorResult("pod and command are required"), nil
+	}
+
+	client, err := ts.manager.Resolve(in.Context)
+	if err != nil {
+		return errorResult("%v", err), nil
+	}
+
+	out, errOut, err := execInPod(ctx, client, in.Namespace, in.Pod, in.Container, in.Command)
+	if err != nil {
+		return errorResult("exec failed: %v\nstdout: %s\nstderr: %s", err, out, errOut), nil
+	}
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: out + errOut}}}, nil
+}
@@ -18,16 +18,20 @@ func NewKubectlTool() mcp.Tool {
 		Name:        "kubectl",
 		Description: "Execute a kubectl command. Use this tool to interact with the Kubernetes cluster configured in the local kubeconfig.",
 		InputSchema: mustGenerateSchema(reflect.TypeOf(struct {
-			Args []string `json:"args"`
+			Args    []string `json:"args"`
+			Context string   `json:"context,omitempty"`
 		}{})),
 	}
 }
 
 // HandleKubectlCommand executes the kubectl command
 // It receives a list of arguments, executes "kubectl" with those arguments, and returns the output.
+// If Context is set, "--context <name>" is passed to kubectl so it targets
+// that kubeconfig context instead of the ambient current-context.
 func HandleKubectlCommand(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	var args struct {
-		Args []string `json:"args"`
+		Args    []string `json:"args"`
+		Context string   `json:"context,omitempty"`
 	}
 	if err := json.Unmarshal(request.Params.Arguments, &args); err != nil {
 		return &mcp.CallToolResult{
@@ -54,7 +58,11 @@ func HandleKubectlCommand(ctx context.Context, request *mcp.CallToolRequest) (*m
 
 	// Execute kubectl
 	// We use CommandContext to respect the context cancellation.
-	cmd := exec.CommandContext(ctx, "kubectl", args.Args...)
+	kubectlArgs := args.Args
+	if args.Context != "" {
+		kubectlArgs = append([]string{"--context", args.Context}, kubectlArgs...)
+	}
+	cmd := exec.CommandContext(ctx, "kubectl", kubectlArgs...)
 	output, err := cmd.CombinedOutput()
 
 	result := &mcp.CallToolResult{
@@ -0,0 +1,48 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// LoadFromConfigMap builds a Policy from a ConfigMap's data, using the same
+// keys CLI flags populate: allow_patterns and deny_patterns (newline
+// separated regexes), read_only ("true"/"false"), timeout_seconds and
+// max_output_bytes. A missing key leaves the corresponding field at its zero
+// value (no restriction). The returned Policy still needs New to compile
+// its regexes before use.
+func LoadFromConfigMap(ctx context.Context, client kubernetes.Interface, namespace, name string) (Policy, error) {
+	cm, err := client.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return Policy{}, fmt.Errorf("failed to load policy ConfigMap %s/%s: %w", namespace, name, err)
+	}
+
+	var p Policy
+	if v := strings.TrimSpace(cm.Data["allow_patterns"]); v != "" {
+		p.Allow = strings.Split(v, "\n")
+	}
+	if v := strings.TrimSpace(cm.Data["deny_patterns"]); v != "" {
+		p.Deny = strings.Split(v, "\n")
+	}
+	if v := cm.Data["read_only"]; v != "" {
+		p.ReadOnly, _ = strconv.ParseBool(v)
+	}
+	if v := cm.Data["timeout_seconds"]; v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			p.Timeout = time.Duration(secs) * time.Second
+		}
+	}
+	if v := cm.Data["max_output_bytes"]; v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			p.MaxOutputBytes = n
+		}
+	}
+
+	return p, nil
+}
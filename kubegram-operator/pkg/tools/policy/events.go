@@ -0,0 +1,63 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// EventRecorder forwards audit records to a sink outside of slog, such as a
+// Kubernetes Event on the operator's own Pod.
+type EventRecorder interface {
+	Record(toolName, sessionID string, failed bool, duration time.Duration)
+}
+
+// PodEventRecorder emits a Kubernetes Event against the operator's own Pod
+// for every audited tool call, so cluster-side tooling that watches Events
+// (rather than Pod logs) can see tool usage.
+type PodEventRecorder struct {
+	Client    kubernetes.Interface
+	Namespace string
+	PodName   string
+}
+
+// Record creates the Event. Failures to create it are logged via slog and
+// otherwise swallowed — audit logging must never fail the tool call it is
+// describing.
+func (r *PodEventRecorder) Record(toolName, sessionID string, failed bool, duration time.Duration) {
+	reason := "ToolInvoked"
+	eventType := corev1.EventTypeNormal
+	if failed {
+		reason = "ToolFailed"
+		eventType = corev1.EventTypeWarning
+	}
+
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-%s-", r.PodName, toolName),
+			Namespace:    r.Namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Name:      r.PodName,
+			Namespace: r.Namespace,
+		},
+		Reason:         reason,
+		Message:        fmt.Sprintf("tool=%s session=%s duration=%s", toolName, sessionID, duration),
+		Type:           eventType,
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+		Source:         corev1.EventSource{Component: "kubegram-operator"},
+	}
+
+	if _, err := r.Client.CoreV1().Events(r.Namespace).Create(context.Background(), event, metav1.CreateOptions{}); err != nil {
+		slog.Warn("failed to record tool-call audit event", "error", err)
+	}
+}
@@ -0,0 +1,131 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ToolHandler matches the signature every MCP tool handler in this repo
+// implements. It is an alias, not a distinct type, so wrapped handlers
+// remain assignable to mcp.ToolHandler at server.AddTool call sites.
+type ToolHandler = mcp.ToolHandler
+
+// Wrap returns a ToolHandler that enforces p's Timeout and MaxOutputBytes
+// around next and emits a structured audit record for every call. It does
+// not apply CheckCommand/CheckKubectl; use WrapBash/WrapKubectl for tools
+// that run arbitrary commands.
+func (p *Policy) Wrap(toolName string, next ToolHandler) ToolHandler {
+	return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+
+		if p.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+			defer cancel()
+		}
+
+		result, err := next(ctx, request)
+		p.truncateResult(result)
+		p.audit(toolName, request, result, err, time.Since(start))
+		return result, err
+	}
+}
+
+// WrapBash wraps a HandleBashCommand-shaped handler with CheckCommand in
+// addition to Wrap's timeout/output/audit behavior.
+func (p *Policy) WrapBash(next ToolHandler) ToolHandler {
+	return p.Wrap("bash", func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Command string `json:"command"`
+		}
+		if err := json.Unmarshal(request.Params.Arguments, &args); err == nil {
+			if err := p.CheckCommand(args.Command); err != nil {
+				return denyResult(err), nil
+			}
+		}
+		return next(ctx, request)
+	})
+}
+
+// WrapKubectl wraps a HandleKubectlCommand-shaped handler with CheckKubectl
+// in addition to Wrap's timeout/output/audit behavior.
+func (p *Policy) WrapKubectl(next ToolHandler) ToolHandler {
+	return p.Wrap("kubectl", func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Args []string `json:"args"`
+		}
+		if err := json.Unmarshal(request.Params.Arguments, &args); err == nil {
+			if err := p.CheckKubectl(args.Args); err != nil {
+				return denyResult(err), nil
+			}
+		}
+		return next(ctx, request)
+	})
+}
+
+// WrapMutatingVerb wraps a native (non-kubectl) tool handler that mutates
+// cluster state with Wrap's timeout/output/audit behavior plus a ReadOnly
+// check against verb (one of the same verbs CheckKubectl rejects in
+// read-only mode, e.g. "delete", "apply"). Used for tools like k8s_delete
+// that don't go through CheckCommand/CheckKubectl's command-line parsing,
+// so --policy-read-only still covers them.
+func (p *Policy) WrapMutatingVerb(toolName, verb string, next ToolHandler) ToolHandler {
+	return p.Wrap(toolName, func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if p.ReadOnly && mutatingKubectlVerbs[verb] {
+			return denyResult(fmt.Errorf("verb %q is not allowed in read-only mode", verb)), nil
+		}
+		return next(ctx, request)
+	})
+}
+
+func denyResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}},
+	}
+}
+
+func (p *Policy) truncateResult(result *mcp.CallToolResult) {
+	if result == nil {
+		return
+	}
+	for _, c := range result.Content {
+		if text, ok := c.(*mcp.TextContent); ok {
+			text.Text = p.Truncate(text.Text)
+		}
+	}
+}
+
+// audit emits a structured slog record of the call (tool name, args, caller
+// session id, exit status and duration), then forwards the same record to
+// p.EventRecorder if one is configured.
+func (p *Policy) audit(toolName string, request *mcp.CallToolRequest, result *mcp.CallToolResult, err error, duration time.Duration) {
+	failed := err != nil || (result != nil && result.IsError)
+	session := sessionID(request)
+
+	slog.Info("mcp tool call",
+		"tool", toolName,
+		"session", session,
+		"args", string(request.Params.Arguments),
+		"failed", failed,
+		"duration", duration,
+	)
+
+	if p.EventRecorder != nil {
+		p.EventRecorder.Record(toolName, session, failed, duration)
+	}
+}
+
+// sessionID extracts the calling MCP session's id, falling back to
+// "unknown" for requests with no associated session (e.g. in unit tests).
+func sessionID(request *mcp.CallToolRequest) string {
+	if request == nil || request.Session == nil {
+		return "unknown"
+	}
+	return request.Session.ID()
+}
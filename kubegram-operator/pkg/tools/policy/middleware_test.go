@@ -0,0 +1,54 @@
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestWrapMutatingVerb_ReadOnlyRejectsMutatingVerb(t *testing.T) {
+	p, err := New(Policy{ReadOnly: true})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	called := false
+	next := func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return &mcp.CallToolResult{}, nil
+	}
+
+	result, err := p.WrapMutatingVerb("k8s_delete", "delete", next)(context.Background(), &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{}})
+	if err != nil {
+		t.Fatalf("WrapMutatingVerb: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected a deny result in read-only mode")
+	}
+	if called {
+		t.Error("next should not be called once the verb is rejected")
+	}
+}
+
+func TestWrapMutatingVerb_PassesThroughWhenNotReadOnly(t *testing.T) {
+	p, err := New(Policy{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	called := false
+	next := func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return &mcp.CallToolResult{}, nil
+	}
+
+	result, err := p.WrapMutatingVerb("k8s_delete", "delete", next)(context.Background(), &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{}})
+	if err != nil {
+		t.Fatalf("WrapMutatingVerb: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected next's result to pass through unmodified outside read-only mode")
+	}
+	if !called {
+		t.Error("expected next to be called")
+	}
+}
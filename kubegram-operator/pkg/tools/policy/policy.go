@@ -0,0 +1,132 @@
+// Package policy enforces command allow/deny rules, read-only mode, output
+// limits and audit logging around MCP tool calls, so tools that can run
+// arbitrary commands (bash, kubectl) are safe to expose over SSE to LLM
+// clients.
+package policy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Policy configures the guardrails applied to tool invocations. The
+// command-specific checks (CheckCommand/CheckKubectl) are only consulted by
+// WrapBash/WrapKubectl; Wrap applies the timeout, output-size and
+// audit-logging behavior to any tool.
+type Policy struct {
+	// Allow and Deny are regexes matched against a command's full text
+	// (e.g. "kubectl get pods" or "echo hi"). Deny takes precedence over
+	// Allow. If Allow is non-empty, only commands matching an Allow
+	// pattern are permitted; otherwise everything not denied is allowed.
+	// ReadOnly is a separate, verb-based restriction (see below) and does
+	// not by itself require an allowlist match.
+	Allow []string
+	Deny  []string
+	// ReadOnly additionally rejects mutating kubectl verbs
+	// (apply/delete/patch/edit/exec/cp/replace/create/scale) regardless of
+	// the allowlist.
+	ReadOnly bool
+	// Timeout bounds how long a single tool call may run, in addition to
+	// whatever deadline the caller's context already carries. Zero means
+	// no additional timeout.
+	Timeout time.Duration
+	// MaxOutputBytes truncates a CallToolResult's text content beyond this
+	// size. Zero means unlimited.
+	MaxOutputBytes int
+	// EventRecorder, if set, receives every audit record in addition to
+	// the slog record Wrap always emits.
+	EventRecorder EventRecorder
+
+	allowRe []*regexp.Regexp
+	denyRe  []*regexp.Regexp
+}
+
+// mutatingKubectlVerbs are rejected in ReadOnly mode regardless of the
+// allowlist. Also consulted by WrapMutatingVerb for native (non-kubectl)
+// tools that mutate cluster state without going through CheckKubectl.
+var mutatingKubectlVerbs = map[string]bool{
+	"apply": true, "delete": true, "patch": true, "edit": true,
+	"exec": true, "cp": true, "replace": true, "create": true, "scale": true,
+}
+
+// shellMetacharacters matches the constructs bash -c treats specially
+// enough to run more than the single command an Allow pattern was written
+// to match: separators (;, &, &&, ||), pipes (|), command/process
+// substitution ($(...), `...`, <(...), >(...)) and newlines. CheckCommand's
+// Allow/Deny regexes only ever see the whole command string, so they have
+// no way to validate anything that rides along after one of these.
+var shellMetacharacters = regexp.MustCompile("[;&|`\n]|\\$\\(|<\\(|>\\(")
+
+// New compiles p's Allow/Deny patterns, returning an error if any pattern is
+// not a valid regex. Call it once after populating Policy's exported fields
+// (from CLI flags or LoadFromConfigMap) and before passing it to Wrap/WrapBash/WrapKubectl.
+func New(p Policy) (*Policy, error) {
+	compiled := p
+	for _, pattern := range p.Allow {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allow pattern %q: %w", pattern, err)
+		}
+		compiled.allowRe = append(compiled.allowRe, re)
+	}
+	for _, pattern := range p.Deny {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deny pattern %q: %w", pattern, err)
+		}
+		compiled.denyRe = append(compiled.denyRe, re)
+	}
+	return &compiled, nil
+}
+
+// CheckCommand validates a bash command line against the deny list, then
+// the allowlist (when Allow is non-empty). ReadOnly has no bash-specific
+// verb to check against, so it is not consulted here; see CheckKubectl for
+// the kubectl-verb-aware equivalent.
+//
+// Because cmd is executed via "bash -c" (see HandleBashCommand), an Allow
+// pattern matching the whole string is not enough to vet it: a command
+// like "echo hi; rm -rf /tmp/demo" matches an Allow pattern of "^echo " in
+// full while still running a second, unvetted command. Once an allowlist
+// is configured, CheckCommand rejects any command containing a shell
+// metacharacter outright rather than trying to parse shell grammar.
+func (p *Policy) CheckCommand(cmd string) error {
+	for _, re := range p.denyRe {
+		if re.MatchString(cmd) {
+			return fmt.Errorf("command %q is denied by policy (matches %q)", cmd, re.String())
+		}
+	}
+	if len(p.allowRe) == 0 {
+		return nil
+	}
+	if m := shellMetacharacters.FindString(cmd); m != "" {
+		return fmt.Errorf("command %q contains shell metacharacter %q, which could run an unvetted command alongside an allowed one", cmd, m)
+	}
+	for _, re := range p.allowRe {
+		if re.MatchString(cmd) {
+			return nil
+		}
+	}
+	return fmt.Errorf("command %q is not on the allowlist", cmd)
+}
+
+// CheckKubectl validates kubectl arguments, rejecting mutating verbs in
+// ReadOnly mode before falling back to the same allow/deny check as
+// CheckCommand. args[0] is conventionally the kubectl verb (get, apply, ...).
+func (p *Policy) CheckKubectl(args []string) error {
+	if p.ReadOnly && len(args) > 0 && mutatingKubectlVerbs[args[0]] {
+		return fmt.Errorf("kubectl verb %q is not allowed in read-only mode", args[0])
+	}
+	return p.CheckCommand("kubectl " + strings.Join(args, " "))
+}
+
+// Truncate shortens output to MaxOutputBytes, appending a marker noting how
+// much was cut. A MaxOutputBytes of zero or less disables truncation.
+func (p *Policy) Truncate(output string) string {
+	if p.MaxOutputBytes <= 0 || len(output) <= p.MaxOutputBytes {
+		return output
+	}
+	return output[:p.MaxOutputBytes] + fmt.Sprintf("\n... [truncated %d bytes]", len(output)-p.MaxOutputBytes)
+}
@@ -0,0 +1,84 @@
+package policy
+
+import "testing"
+
+func TestCheckCommand_ReadOnlyWithEmptyAllowPermitsReads(t *testing.T) {
+	p, err := New(Policy{ReadOnly: true})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := p.CheckCommand("echo hi"); err != nil {
+		t.Errorf("CheckCommand with ReadOnly and no Allow patterns should not force an allowlist check, got: %v", err)
+	}
+}
+
+func TestCheckCommand_DenyWinsEvenInReadOnly(t *testing.T) {
+	p, err := New(Policy{ReadOnly: true, Deny: []string{"rm -rf"}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := p.CheckCommand("rm -rf /"); err == nil {
+		t.Error("expected denied command to be rejected")
+	}
+}
+
+func TestCheckCommand_AllowlistStillEnforcedWhenSet(t *testing.T) {
+	p, err := New(Policy{Allow: []string{"^echo "}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := p.CheckCommand("echo hi"); err != nil {
+		t.Errorf("expected allowed command to pass, got: %v", err)
+	}
+	if err := p.CheckCommand("rm -rf /"); err == nil {
+		t.Error("expected command not matching the allowlist to be rejected")
+	}
+}
+
+func TestCheckKubectl_ReadOnlyRejectsMutatingVerbs(t *testing.T) {
+	p, err := New(Policy{ReadOnly: true})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := p.CheckKubectl([]string{"delete", "pod", "foo"}); err == nil {
+		t.Error("expected mutating verb to be rejected in read-only mode")
+	}
+}
+
+func TestCheckKubectl_ReadOnlyWithEmptyAllowPermitsGet(t *testing.T) {
+	p, err := New(Policy{ReadOnly: true})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := p.CheckKubectl([]string{"get", "pods"}); err != nil {
+		t.Errorf("expected read verb to be permitted in read-only mode with no Allow patterns, got: %v", err)
+	}
+}
+
+func TestCheckCommand_AllowlistRejectsShellMetacharacters(t *testing.T) {
+	p, err := New(Policy{Allow: []string{"^echo "}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	for _, cmd := range []string{
+		"echo hi; rm -rf /tmp/demo",
+		"echo hi && rm -rf /tmp/demo",
+		"echo hi | rm -rf /tmp/demo",
+		"echo $(rm -rf /tmp/demo)",
+		"echo `rm -rf /tmp/demo`",
+	} {
+		if err := p.CheckCommand(cmd); err == nil {
+			t.Errorf("CheckCommand(%q) = nil, want error: matches the allowlist in full but smuggles a second command past it", cmd)
+		}
+	}
+}
+
+func TestCheckCommand_DenyStillAppliesToShellMetacharacterCommands(t *testing.T) {
+	p, err := New(Policy{Deny: []string{"rm -rf"}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := p.CheckCommand("echo hi; rm -rf /tmp/demo"); err == nil {
+		t.Error("expected denied command to be rejected even with no Allow patterns configured")
+	}
+}
@@ -0,0 +1,201 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+
+	"github.com/kubegram/kubegram-operator/pkg/kube/contextmgr"
+)
+
+// PortForwardStatus describes a single active (or just-stopped) forward, for
+// the list_port_forwards tool and for forwards InitProxies opens internally.
+type PortForwardStatus struct {
+	ID         string    `json:"id"`
+	Namespace  string    `json:"namespace"`
+	Target     string    `json:"target"`
+	RemotePort int       `json:"remote_port"`
+	LocalPort  int       `json:"local_port"`
+	StartedAt  time.Time `json:"started_at"`
+	ExpiresAt  time.Time `json:"expires_at,omitempty"`
+}
+
+// forward is the registry's internal bookkeeping for one portforward.PortForwarder.
+type forward struct {
+	PortForwardStatus
+	stopCh chan struct{}
+	once   sync.Once
+}
+
+func (f *forward) stop() {
+	f.once.Do(func() { close(f.stopCh) })
+}
+
+// PortForwardRegistry tracks every forward opened through port_forward (or
+// opened internally by InitProxies to reach an upstream MCP server), so they
+// can be listed and stopped by id. Each forward is torn down when the ctx it
+// was started with is cancelled; the registry has no method that stops all of
+// them at once.
+type PortForwardRegistry struct {
+	mu       sync.Mutex
+	forwards map[string]*forward
+	nextID   int
+}
+
+// NewPortForwardRegistry creates an empty registry.
+func NewPortForwardRegistry() *PortForwardRegistry {
+	return &PortForwardRegistry{forwards: map[string]*forward{}}
+}
+
+// Start opens a port-forward to a Pod (resolving target as a Pod name
+// first, falling back to a Service name) and returns its status once the
+// tunnel is ready. localPort of 0 picks a free local port. The forward is
+// stopped, and removed from the registry, when ctx is cancelled, duration
+// elapses (if non-zero), or Stop(id) is called.
+func (r *PortForwardRegistry) Start(ctx context.Context, client *contextmgr.Client, namespace, target string, remotePort, localPort int, duration time.Duration) (PortForwardStatus, error) {
+	pod, err := resolveToPod(ctx, client, namespace, target)
+	if err != nil {
+		return PortForwardStatus{}, err
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(client.Config)
+	if err != nil {
+		return PortForwardStatus{}, fmt.Errorf("failed to build SPDY round tripper: %w", err)
+	}
+
+	req := client.Typed.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("portforward")
+
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	stopCh := make(chan struct{})
+	readyCh := make(chan struct{})
+	var stdout, stderr bytes.Buffer
+
+	pf, err := portforward.New(dialer, []string{fmt.Sprintf("%d:%d", localPort, remotePort)}, stopCh, readyCh, &stdout, &stderr)
+	if err != nil {
+		return PortForwardStatus{}, fmt.Errorf("failed to set up port-forward to %s/%s: %w", namespace, pod, err)
+	}
+
+	forwardErrCh := make(chan error, 1)
+	go func() { forwardErrCh <- pf.ForwardPorts() }()
+
+	select {
+	case <-readyCh:
+	case err := <-forwardErrCh:
+		return PortForwardStatus{}, fmt.Errorf("port-forward to %s/%s exited before becoming ready: %w (%s)", namespace, pod, err, stderr.String())
+	case <-time.After(30 * time.Second):
+		close(stopCh)
+		return PortForwardStatus{}, fmt.Errorf("timed out waiting for port-forward to %s/%s to become ready", namespace, pod)
+	}
+
+	ports, err := pf.GetPorts()
+	if err != nil || len(ports) == 0 {
+		close(stopCh)
+		return PortForwardStatus{}, fmt.Errorf("failed to determine local port for %s/%s: %w", namespace, pod, err)
+	}
+
+	r.mu.Lock()
+	r.nextID++
+	id := fmt.Sprintf("pf-%d", r.nextID)
+	status := PortForwardStatus{
+		ID:         id,
+		Namespace:  namespace,
+		Target:     target,
+		RemotePort: remotePort,
+		LocalPort:  int(ports[0].Local),
+		StartedAt:  time.Now(),
+	}
+	if duration > 0 {
+		status.ExpiresAt = status.StartedAt.Add(duration)
+	}
+	fwd := &forward{PortForwardStatus: status, stopCh: stopCh}
+	r.forwards[id] = fwd
+	r.mu.Unlock()
+
+	go r.superviseForward(ctx, fwd, duration)
+
+	return status, nil
+}
+
+// superviseForward stops fwd (and removes it from the registry) when ctx is
+// cancelled, duration elapses, or Stop closes fwd.stopCh. It does not watch
+// for the forward exiting on its own; such a forward stays listed until one
+// of those happens.
+func (r *PortForwardRegistry) superviseForward(ctx context.Context, fwd *forward, duration time.Duration) {
+	var timeout <-chan time.Time
+	if duration > 0 {
+		timer := time.NewTimer(duration)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-timeout:
+	case <-fwd.stopCh:
+	}
+
+	fwd.stop()
+
+	r.mu.Lock()
+	delete(r.forwards, fwd.ID)
+	r.mu.Unlock()
+}
+
+// List returns the status of every currently active forward.
+func (r *PortForwardRegistry) List() []PortForwardStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	statuses := make([]PortForwardStatus, 0, len(r.forwards))
+	for _, fwd := range r.forwards {
+		statuses = append(statuses, fwd.PortForwardStatus)
+	}
+	return statuses
+}
+
+// Stop tears down the forward with the given id. It is a no-op (returning
+// an error) if no such forward is active.
+func (r *PortForwardRegistry) Stop(id string) error {
+	r.mu.Lock()
+	fwd, ok := r.forwards[id]
+	r.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no active port-forward with id %q", id)
+	}
+	fwd.stop()
+	return nil
+}
+
+// resolveToPod resolves target to the name of a ready backing Pod: if
+// target names a Pod directly, that Pod is used; otherwise target is
+// looked up as a Service and one of its ready endpoint Pods is chosen.
+func resolveToPod(ctx context.Context, client *contextmgr.Client, namespace, target string) (string, error) {
+	if pod, err := client.Typed.CoreV1().Pods(namespace).Get(ctx, target, metav1.GetOptions{}); err == nil {
+		return pod.Name, nil
+	}
+
+	endpoints, err := client.Typed.CoreV1().Endpoints(namespace).Get(ctx, target, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("%q is neither a Pod nor a Service with endpoints in namespace %s: %w", target, namespace, err)
+	}
+	for _, subset := range endpoints.Subsets {
+		for _, addr := range subset.Addresses {
+			if addr.TargetRef != nil && addr.TargetRef.Kind == "Pod" {
+				return addr.TargetRef.Name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("service %s/%s has no ready endpoint pods", namespace, target)
+}
@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/kubegram/kubegram-operator/pkg/kube/contextmgr"
+)
+
+// PortForwardTool exposes port_forward/list_port_forwards/stop_port_forward
+// as MCP tools backed by a shared PortForwardRegistry, so forwards opened by
+// one call can be listed and stopped by another.
+type PortForwardTool struct {
+	manager  *contextmgr.Manager
+	registry *PortForwardRegistry
+}
+
+// NewPortForwardToolset creates a PortForwardTool backed by the given
+// contextmgr.Manager and registry. Passing in the same registry used by
+// InitProxies lets forwards opened to reach an upstream MCP server show up
+// alongside caller-initiated ones in list_port_forwards.
+func NewPortForwardToolset(manager *contextmgr.Manager, registry *PortForwardRegistry) *PortForwardTool {
+	return &PortForwardTool{manager: manager, registry: registry}
+}
+
+// portForwardInput is the input schema for the port_forward tool.
+type portForwardInput struct {
+	Namespace    string `json:"namespace"`
+	Target       string `json:"target"`
+	RemotePort   int    `json:"remote_port"`
+	LocalPort    int    `json:"local_port,omitempty"`
+	DurationSecs int    `json:"duration_seconds,omitempty"`
+	Context      string `json:"context,omitempty"`
+}
+
+// NewPortForwardTool creates the port_forward tool, which opens a forwarded
+// connection to a Pod or Service and returns a local address for it.
+func (pf *PortForwardTool) NewPortForwardTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "port_forward",
+		Description: "Open a port-forward to a Pod or Service (resolved to one of its ready endpoint Pods) and return a short-lived local address for it. local_port of 0 (the default) picks a free local port. duration_seconds of 0 keeps the forward open until stop_port_forward is called or the server shuts down.",
+		InputSchema: mustGenerateSchema(reflect.TypeOf(portForwardInput{})),
+	}
+}
+
+// HandlePortForward executes the port_forward tool.
+func (pf *PortForwardTool) HandlePortForward(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var in portForwardInput
+	if err := json.Unmarshal(request.Params.Arguments, &in); err != nil {
+		return errResult("failed to unmarshal arguments: %v", err), nil
+	}
+	if in.Namespace == "" || in.Target == "" || in.RemotePort == 0 {
+		return errResult("namespace, target and remote_port are required"), nil
+	}
+
+	client, err := pf.manager.Resolve(in.Context)
+	if err != nil {
+		return errResult("%v", err), nil
+	}
+
+	var duration time.Duration
+	if in.DurationSecs > 0 {
+		duration = time.Duration(in.DurationSecs) * time.Second
+	}
+
+	status, err := pf.registry.Start(ctx, client, in.Namespace, in.Target, in.RemotePort, in.LocalPort, duration)
+	if err != nil {
+		return errResult("failed to open port-forward to %s/%s: %v", in.Namespace, in.Target, err), nil
+	}
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{
+		Text: fmt.Sprintf("Forwarding %s/%s:%d -> 127.0.0.1:%d (id=%s)", in.Namespace, in.Target, in.RemotePort, status.LocalPort, status.ID),
+	}}}, nil
+}
+
+// NewListPortForwardsTool creates the list_port_forwards tool.
+func (pf *PortForwardTool) NewListPortForwardsTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "list_port_forwards",
+		Description: "List every port-forward currently open through port_forward (or opened internally to reach a proxied upstream MCP server).",
+		InputSchema: mustGenerateSchema(reflect.TypeOf(struct{}{})),
+	}
+}
+
+// HandleListPortForwards executes the list_port_forwards tool.
+func (pf *PortForwardTool) HandleListPortForwards(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(pf.registry.List(), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(data)}}}, nil
+}
+
+// stopPortForwardInput is the input schema for the stop_port_forward tool.
+type stopPortForwardInput struct {
+	ID string `json:"id"`
+}
+
+// NewStopPortForwardTool creates the stop_port_forward tool.
+func (pf *PortForwardTool) NewStopPortForwardTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "stop_port_forward",
+		Description: "Stop a port-forward previously opened by port_forward, identified by the id returned at the time.",
+		InputSchema: mustGenerateSchema(reflect.TypeOf(stopPortForwardInput{})),
+	}
+}
+
+// HandleStopPortForward executes the stop_port_forward tool.
+func (pf *PortForwardTool) HandleStopPortForward(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var in stopPortForwardInput
+	if err := json.Unmarshal(request.Params.Arguments, &in); err != nil {
+		return errResult("failed to unmarshal arguments: %v", err), nil
+	}
+	if in.ID == "" {
+		return errResult("id is required"), nil
+	}
+
+	if err := pf.registry.Stop(in.ID); err != nil {
+		return errResult("%v", err), nil
+	}
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{
+		Text: fmt.Sprintf("stopped port-forward %s", in.ID),
+	}}}, nil
+}
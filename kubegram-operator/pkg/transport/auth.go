@@ -0,0 +1,117 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// SessionAuth is the credential/scope snapshot a transport (or pkg/mcp's
+// ProxyClient) captures when it opens a session. AuthChecker re-fetches it
+// on a fixed interval and compares the result against the SessionAuth the
+// session was opened with, so a rotated token or an RBAC change can sever
+// the connection rather than silently running with stale authorization.
+type SessionAuth struct {
+	Token               string
+	UserID              string
+	AllowedToolPrefixes []string
+	Expiry              time.Time
+}
+
+// Equal reports whether a and b represent the same authorization. Two nil
+// pointers are equal; a nil and a non-nil are not.
+func (a *SessionAuth) Equal(b *SessionAuth) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Token != b.Token || a.UserID != b.UserID || !a.Expiry.Equal(b.Expiry) {
+		return false
+	}
+	return reflect.DeepEqual(a.AllowedToolPrefixes, b.AllowedToolPrefixes)
+}
+
+// AuthCheckerFunc re-validates the credentials/scopes used to open a
+// session, returning the current SessionAuth. An error return means the
+// check itself failed (e.g. the authorization endpoint returned a
+// non-200), which AuthChecker treats the same as a changed SessionAuth.
+type AuthCheckerFunc func(ctx context.Context) (*SessionAuth, error)
+
+// ErrAuthChanged is the error AuthChecker reports once Check's result no
+// longer matches Template, or Check itself fails. Transports treat it like
+// any other "tear down and redial" signal, so the next connection attempt
+// picks up fresh credentials.
+var ErrAuthChanged = errors.New("session authorization changed, reconnecting")
+
+// AuthChecker periodically re-validates the credentials/scopes a session
+// was opened with. It's the shared piece behind periodic re-authorization
+// in WebSocketTransport and StreamableHTTPTransport (which redial an
+// outbound connection when it fires) and pkg/mcp's ProxyClient (which
+// redials/respawns a proxied MCP session the same way) — borrowed from the
+// pattern GitLab's terminal websockets use to re-check a session's JWT.
+type AuthChecker struct {
+	// Template is the SessionAuth the session was opened with. Check's
+	// result is compared against it on every tick.
+	Template *SessionAuth
+	// Interval is how often Check runs. Start is a no-op if Interval <= 0.
+	Interval time.Duration
+	// Check re-fetches the current SessionAuth.
+	Check AuthCheckerFunc
+
+	startOnce sync.Once
+	changedCh chan error
+}
+
+// Clone returns a fresh *AuthChecker with the same Template, Interval and
+// Check, but its own startOnce/changedCh — so a caller that reconnects
+// repeatedly (see WebSocketTransport, StreamableHTTPTransport, ProxyClient)
+// can arm a new one per connection attempt instead of copying the
+// pointee (which would copy AuthChecker's embedded sync.Once and defeat
+// Start's once-only-start guarantee).
+func (c *AuthChecker) Clone() *AuthChecker {
+	if c == nil {
+		return nil
+	}
+	return &AuthChecker{Template: c.Template, Interval: c.Interval, Check: c.Check}
+}
+
+// Start launches the periodic check in its own goroutine and returns a
+// channel that receives ErrAuthChanged the first time Check's result
+// diverges from Template or Check errors; nothing further is ever sent on
+// it afterwards. It returns nil if Check is nil or Interval <= 0, so a
+// caller can always select on it (a nil channel simply never fires).
+// Start only launches its goroutine on the first call; later calls return
+// the same channel.
+func (c *AuthChecker) Start(ctx context.Context) <-chan error {
+	if c == nil || c.Check == nil || c.Interval <= 0 {
+		return nil
+	}
+	c.startOnce.Do(func() {
+		c.changedCh = make(chan error, 1)
+		go c.run(ctx)
+	})
+	return c.changedCh
+}
+
+func (c *AuthChecker) run(ctx context.Context) {
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current, err := c.Check(ctx)
+			if err == nil && c.Template.Equal(current) {
+				continue
+			}
+			select {
+			case c.changedCh <- ErrAuthChanged:
+			default:
+			}
+			return
+		}
+	}
+}
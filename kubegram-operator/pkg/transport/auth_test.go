@@ -0,0 +1,54 @@
+package transport
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAuthChecker_ClonePreservesFieldsWithFreshState(t *testing.T) {
+	template := &SessionAuth{Token: "t1"}
+	check := func(ctx context.Context) (*SessionAuth, error) { return template, nil }
+	c := &AuthChecker{Template: template, Interval: time.Second, Check: check}
+	c.Start(context.Background())
+
+	clone := c.Clone()
+	if clone.Template != c.Template || clone.Interval != c.Interval {
+		t.Fatalf("Clone did not preserve Template/Interval: got %+v, want Template=%v Interval=%v", clone, c.Template, c.Interval)
+	}
+	if clone.changedCh != nil {
+		t.Error("Clone should not inherit the original's changedCh")
+	}
+
+	// Clone's own sync.Once must not have been consumed by c.Start above.
+	ch := clone.Start(context.Background())
+	if ch == nil {
+		t.Error("expected Clone's Start to arm its own channel")
+	}
+}
+
+func TestAuthChecker_StartFiresOnAuthChange(t *testing.T) {
+	template := &SessionAuth{Token: "t1"}
+	current := &SessionAuth{Token: "t2"}
+	check := func(ctx context.Context) (*SessionAuth, error) { return current, nil }
+	c := &AuthChecker{Template: template, Interval: time.Millisecond, Check: check}
+
+	ch := c.Start(context.Background())
+	select {
+	case err := <-ch:
+		if err != ErrAuthChanged {
+			t.Errorf("got error %v, want ErrAuthChanged", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for AuthChecker to detect the token change")
+	}
+}
+
+func TestAuthChecker_StartNilWithoutIntervalOrCheck(t *testing.T) {
+	if ch := (&AuthChecker{}).Start(context.Background()); ch != nil {
+		t.Error("expected nil channel when Interval and Check are unset")
+	}
+	if ch := (*AuthChecker)(nil).Start(context.Background()); ch != nil {
+		t.Error("expected nil channel for a nil *AuthChecker")
+	}
+}
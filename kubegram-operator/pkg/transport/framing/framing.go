@@ -0,0 +1,155 @@
+// Package framing gives raw byte streams — a net.Pipe, a unix socket, an SSH
+// channel, an exec.Cmd's stdio — the message boundaries JSON-RPC itself
+// doesn't provide, so a transport built on one of them doesn't need to guess
+// where one message ends and the next begins (or rely on every Write being
+// delivered atomically, which most of these streams don't guarantee).
+package framing
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Codec reads and writes one JSON-RPC message's raw bytes at a time from a
+// shared stream.
+type Codec interface {
+	// ReadFrame reads the next complete message from r.
+	ReadFrame(r *bufio.Reader) ([]byte, error)
+	// WriteFrame writes data as one complete message to w.
+	WriteFrame(w io.Writer, data []byte) error
+}
+
+// LSPCodec implements the Content-Length-prefixed framing the Language
+// Server Protocol (and most stdio-based MCP servers) use:
+// "Content-Length: N\r\n\r\n" followed by exactly N bytes of JSON.
+type LSPCodec struct{}
+
+// ReadFrame reads a Content-Length header block followed by its payload.
+func (LSPCodec) ReadFrame(r *bufio.Reader) ([]byte, error) {
+	contentLength := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid Content-Length header %q: %w", value, err)
+			}
+			contentLength = n
+		}
+	}
+	if contentLength < 0 {
+		return nil, fmt.Errorf("framing: missing Content-Length header")
+	}
+
+	data := make([]byte, contentLength)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// WriteFrame writes data prefixed with its Content-Length header.
+func (LSPCodec) WriteFrame(w io.Writer, data []byte) error {
+	_, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(data), data)
+	return err
+}
+
+// NewlineCodec implements newline-delimited JSON framing: one message per
+// line. It's a fallback for peers that don't speak Content-Length framing;
+// it works because encoding/json never emits a raw newline inside a
+// marshaled message.
+type NewlineCodec struct{}
+
+// ReadFrame reads up to the next '\n', stripping the trailing line ending.
+func (NewlineCodec) ReadFrame(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadBytes('\n')
+	if err != nil && len(line) == 0 {
+		return nil, err
+	}
+	return bytes.TrimRight(line, "\r\n"), nil
+}
+
+// WriteFrame writes data followed by a newline.
+func (NewlineCodec) WriteFrame(w io.Writer, data []byte) error {
+	_, err := fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// FramedConnection implements mcp.Connection over an io.ReadWriteCloser,
+// using a Codec to delimit messages.
+type FramedConnection struct {
+	rw        io.ReadWriteCloser
+	codec     Codec
+	sessionID string
+
+	readMu  sync.Mutex
+	reader  *bufio.Reader
+	writeMu sync.Mutex
+}
+
+// NewFramedConnection wraps rw as an mcp.Connection, reading and writing
+// whole messages via codec instead of assuming rw preserves message
+// boundaries on its own. sessionID is returned from SessionID(); pass ""
+// if the caller has no meaningful session identifier.
+func NewFramedConnection(rw io.ReadWriteCloser, codec Codec, sessionID string) mcp.Connection {
+	return &FramedConnection{
+		rw:        rw,
+		codec:     codec,
+		sessionID: sessionID,
+		reader:    bufio.NewReader(rw),
+	}
+}
+
+// SessionID returns the session identifier the connection was created with.
+func (c *FramedConnection) SessionID() string {
+	return c.sessionID
+}
+
+// Read blocks until codec has a complete message to decode.
+func (c *FramedConnection) Read(ctx context.Context) (jsonrpc.Message, error) {
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+
+	data, err := c.codec.ReadFrame(c.reader)
+	if err != nil {
+		return nil, err
+	}
+	return jsonrpc.DecodeMessage(data)
+}
+
+// Write encodes message and writes it as one complete frame.
+func (c *FramedConnection) Write(ctx context.Context, message jsonrpc.Message) error {
+	data, err := jsonrpc.EncodeMessage(message)
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.codec.WriteFrame(c.rw, data)
+}
+
+// Close closes the underlying stream.
+func (c *FramedConnection) Close() error {
+	return c.rw.Close()
+}
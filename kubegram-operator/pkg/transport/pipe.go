@@ -0,0 +1,29 @@
+package transport
+
+import (
+	"context"
+	"net"
+
+	"github.com/kubegram/kubegram-operator/pkg/transport/framing"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// PipeTransport implements mcp.Transport over an in-memory net.Conn (e.g.
+// one half of a net.Pipe), using framing.LSPCodec so messages get real
+// boundaries instead of relying on every Write being delivered atomically.
+// It's mainly useful for wiring an mcp.Client and mcp.Server together
+// directly, e.g. in tests (see tests/integration/mcp_server_test.go).
+type PipeTransport struct {
+	Conn net.Conn
+}
+
+// NewPipeTransport wraps conn (one half of a net.Pipe, or any net.Conn) as
+// an mcp.Transport.
+func NewPipeTransport(conn net.Conn) *PipeTransport {
+	return &PipeTransport{Conn: conn}
+}
+
+// Connect returns a framing.FramedConnection around t.Conn.
+func (t *PipeTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	return framing.NewFramedConnection(t.Conn, framing.LSPCodec{}, "pipe-session"), nil
+}
@@ -0,0 +1,107 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/proxy"
+)
+
+// ProxyDialContext returns a dial function suitable for
+// websocket.Dialer.NetDialContext that tunnels through an HTTP(S) CONNECT or
+// SOCKS5 proxy when proxyFunc returns a non-nil URL for the target, and
+// dials directly otherwise. Unlike http.Transport (which resolves proxies
+// and tunnels through them internally), a websocket.Dialer's NetDialContext
+// is just a raw TCP dial function, so this fills in the same behavior by
+// hand: proxyFunc is typically http.ProxyFromEnvironment, which honors
+// HTTP_PROXY/HTTPS_PROXY/NO_PROXY, or http.ProxyURL for a fixed override.
+// For wss:// targets, websocket.Dialer layers the TLS handshake on top of
+// whatever net.Conn this returns, so the TLS upgrade happens inside the
+// tunnel as intended.
+func ProxyDialContext(proxyFunc func(*http.Request) (*url.URL, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		proxyURL, err := proxyFunc(&http.Request{URL: &url.URL{Scheme: "https", Host: addr}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve proxy for %s: %w", addr, err)
+		}
+		if proxyURL == nil {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		}
+
+		switch proxyURL.Scheme {
+		case "socks5", "socks5h":
+			return dialSOCKS5(ctx, proxyURL, network, addr)
+		case "http", "https":
+			return dialHTTPConnect(ctx, proxyURL, addr)
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+		}
+	}
+}
+
+// dialSOCKS5 dials addr through a SOCKS5 proxy at proxyURL.Host, using
+// golang.org/x/net/proxy (net/http's own SOCKS5 support lives behind
+// http.Transport's internal connect method, which isn't reusable here).
+func dialSOCKS5(ctx context.Context, proxyURL *url.URL, network, addr string) (net.Conn, error) {
+	var auth *proxy.Auth
+	if proxyURL.User != nil {
+		auth = &proxy.Auth{User: proxyURL.User.Username()}
+		if pw, ok := proxyURL.User.Password(); ok {
+			auth.Password = pw
+		}
+	}
+
+	dialer, err := proxy.SOCKS5(network, proxyURL.Host, auth, proxy.Direct)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SOCKS5 dialer via %s: %w", proxyURL.Host, err)
+	}
+	if ctxDialer, ok := dialer.(proxy.ContextDialer); ok {
+		return ctxDialer.DialContext(ctx, network, addr)
+	}
+	return dialer.Dial(network, addr)
+}
+
+// dialHTTPConnect dials proxyURL.Host and issues an HTTP CONNECT for addr,
+// returning the tunneled connection once the proxy confirms it with a 200.
+func dialHTTPConnect(ctx context.Context, proxyURL *url.URL, addr string) (net.Conn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", proxyURL.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial proxy %s: %w", proxyURL.Host, err)
+	}
+
+	connectReq := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if proxyURL.User != nil {
+		if pw, ok := proxyURL.User.Password(); ok {
+			connectReq.SetBasicAuth(proxyURL.User.Username(), pw)
+		}
+	}
+
+	if err := connectReq.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send CONNECT to proxy %s: %w", proxyURL.Host, err)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), connectReq)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read CONNECT response from proxy %s: %w", proxyURL.Host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s refused CONNECT to %s: %s", proxyURL.Host, addr, resp.Status)
+	}
+	return conn, nil
+}
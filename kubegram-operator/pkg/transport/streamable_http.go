@@ -0,0 +1,419 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// StreamableHTTPTransport implements mcp.Transport for the MCP 2025
+// Streamable HTTP protocol: JSON-RPC requests are POSTed to a single
+// endpoint, the response is either a single JSON body or an SSE stream, and
+// a long-lived GET to the same endpoint carries server-initiated messages.
+// It is a firewall-friendlier alternative to WebSocketTransport for
+// operators whose network only allows plain HTTP(S) egress.
+type StreamableHTTPTransport struct {
+	URL    string
+	mu     sync.Mutex
+	conn   *StreamableHTTPConnection
+	stopCh chan struct{}
+
+	// ProxyURL, if set, is used instead of the environment's
+	// HTTP_PROXY/HTTPS_PROXY/NO_PROXY (via http.ProxyFromEnvironment) to
+	// reach URL. Supports http(s):// and socks5:// schemes, both of which
+	// net/http's Transport tunnels through on its own.
+	ProxyURL *url.URL
+
+	// AuthChecker, if set, re-validates this transport's session
+	// credentials/scopes on a fixed interval; see
+	// WebSocketTransport.SetAuthChecker for the full behavior.
+	AuthChecker *AuthChecker
+}
+
+// httpClient builds the *http.Client StreamableHTTPConnection sends
+// requests with, honoring t.ProxyURL (or HTTP_PROXY/HTTPS_PROXY/NO_PROXY)
+// for both the POST and the long-lived GET event stream.
+func (t *StreamableHTTPTransport) httpClient() *http.Client {
+	proxyFunc := http.ProxyFromEnvironment
+	if t.ProxyURL != nil {
+		proxyFunc = http.ProxyURL(t.ProxyURL)
+	}
+	return &http.Client{Transport: &http.Transport{Proxy: proxyFunc}}
+}
+
+// NewStreamableHTTPTransport creates a new Streamable HTTP transport
+// targeting the given MCP endpoint URL.
+func NewStreamableHTTPTransport(url string) *StreamableHTTPTransport {
+	return &StreamableHTTPTransport{
+		URL:    url,
+		stopCh: make(chan struct{}),
+	}
+}
+
+// SetAuthChecker configures periodic re-authorization for the sessions
+// this transport opens; see WebSocketTransport.SetAuthChecker for the full
+// behavior. Call it before Start.
+func (t *StreamableHTTPTransport) SetAuthChecker(checker *AuthChecker) {
+	t.mu.Lock()
+	t.AuthChecker = checker
+	t.mu.Unlock()
+}
+
+// Connect opens a StreamableHTTPConnection to the endpoint. It implements
+// mcp.Transport.
+func (t *StreamableHTTPTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	return newStreamableHTTPConnection(ctx, t.URL, "", t.httpClient()), nil
+}
+
+// connect establishes a fresh connection and stores it on the transport.
+// This is an internal helper for Start. resumeEventID, when set, is sent as
+// Last-Event-ID on the reconnecting GET stream so the server can replay any
+// notifications the caller missed.
+func (t *StreamableHTTPTransport) connect(ctx context.Context, resumeEventID string) {
+	conn := newStreamableHTTPConnection(ctx, t.URL, resumeEventID, t.httpClient())
+	t.mu.Lock()
+	t.conn = conn
+	t.mu.Unlock()
+}
+
+// Start connects to the endpoint and starts listening for messages,
+// reconnecting with exponential backoff on failure and resuming the
+// server-push stream from the last seen event id, mirroring
+// WebSocketTransport.Start.
+func (t *StreamableHTTPTransport) Start(ctx context.Context, handler func(jsonrpc.Message)) error {
+	logger := log.Log.WithName("streamable-http-transport")
+	logger.Info("Starting Streamable HTTP transport", "url", t.URL)
+
+	const (
+		initialInterval = 1 * time.Second
+		maxInterval     = 30 * time.Second
+		multiplier      = 2.0
+		resetWindow     = 30 * time.Minute
+	)
+	currentInterval := initialInterval
+	var resumeEventID string
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.stopCh:
+			return nil
+		default:
+		}
+
+		t.connect(ctx, resumeEventID)
+		connectedAt := time.Now()
+		logger.Info("Connected to Streamable HTTP server")
+
+		t.mu.Lock()
+		conn := t.conn
+		t.mu.Unlock()
+
+		// Arm a fresh AuthChecker for this connection, if configured.
+		var authChangedCh <-chan error
+		if t.AuthChecker != nil {
+			authChangedCh = t.AuthChecker.Clone().Start(ctx)
+		}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for {
+				msg, err := conn.Read(ctx)
+				if err != nil {
+					logger.Error(err, "Read error")
+					return
+				}
+				handler(msg)
+			}
+		}()
+
+		select {
+		case <-done:
+			logger.Info("Connection closed, reconnecting...")
+			resumeEventID = conn.LastEventID()
+			conn.Close()
+
+			if time.Since(connectedAt) > resetWindow {
+				currentInterval = initialInterval
+			} else {
+				logger.Info("Connection closed prematurely, applying backoff", "retry_after", currentInterval.String())
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(currentInterval):
+					currentInterval = time.Duration(float64(currentInterval) * multiplier)
+					if currentInterval > maxInterval {
+						currentInterval = maxInterval
+					}
+				}
+			}
+
+		case err := <-authChangedCh:
+			// The session's credentials/scopes no longer match what it was
+			// opened with (or the recheck itself failed); tear down and
+			// redial immediately, without reconnect backoff, so the next
+			// connection picks up fresh ones.
+			logger.Info("Session authorization changed, reconnecting", "reason", err)
+			resumeEventID = conn.LastEventID()
+			conn.Close()
+
+		case <-ctx.Done():
+			conn.Close()
+			return nil
+		case <-t.stopCh:
+			conn.Close()
+			return nil
+		}
+	}
+}
+
+// Close stops the transport and closes its current connection, if any.
+func (t *StreamableHTTPTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var err error
+	if t.conn != nil {
+		err = t.conn.Close()
+		t.conn = nil
+	}
+	select {
+	case <-t.stopCh:
+	default:
+		close(t.stopCh)
+	}
+	return err
+}
+
+// StreamableHTTPConnection implements mcp.Connection over the Streamable
+// HTTP protocol. Write POSTs a JSON-RPC message and decodes either a single
+// JSON response or an SSE stream of responses; a separate long-lived GET
+// carries server-initiated messages. Both feed the same incoming queue that
+// Read drains.
+type StreamableHTTPConnection struct {
+	url        string
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	sessionID   string
+	lastEventID string
+
+	incoming  chan jsonrpc.Message
+	errCh     chan error
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
+
+func newStreamableHTTPConnection(ctx context.Context, url, resumeEventID string, httpClient *http.Client) *StreamableHTTPConnection {
+	c := &StreamableHTTPConnection{
+		url:         url,
+		httpClient:  httpClient,
+		lastEventID: resumeEventID,
+		incoming:    make(chan jsonrpc.Message, 16),
+		errCh:       make(chan error, 1),
+		closeCh:     make(chan struct{}),
+	}
+	go c.runEventStream(ctx)
+	return c
+}
+
+// SessionID returns the Mcp-Session-Id the server assigned on the first
+// response, or "" before that has happened.
+func (c *StreamableHTTPConnection) SessionID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sessionID
+}
+
+// LastEventID returns the id of the last SSE event seen, for resumability
+// on reconnect via Last-Event-ID.
+func (c *StreamableHTTPConnection) LastEventID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastEventID
+}
+
+// Read returns the next message received either as a POST response or on
+// the server-push SSE stream.
+func (c *StreamableHTTPConnection) Read(ctx context.Context) (jsonrpc.Message, error) {
+	select {
+	case msg, ok := <-c.incoming:
+		if !ok {
+			return nil, io.EOF
+		}
+		return msg, nil
+	case err := <-c.errCh:
+		return nil, err
+	case <-c.closeCh:
+		return nil, io.EOF
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Write POSTs message to the endpoint and queues whatever response(s) come
+// back (a single JSON body, or a stream of SSE events) onto the incoming
+// queue for Read to return.
+func (c *StreamableHTTPConnection) Write(ctx context.Context, message jsonrpc.Message) error {
+	data, err := jsonrpc.EncodeMessage(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if sessionID := c.SessionID(); sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", sessionID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to POST message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if sessionID := resp.Header.Get("Mcp-Session-Id"); sessionID != "" {
+		c.mu.Lock()
+		c.sessionID = sessionID
+		c.mu.Unlock()
+	}
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("server returned %s: %s", resp.Status, string(body))
+	}
+
+	// A request that carries only a notification or response (no id
+	// expecting a reply) is acknowledged with 202 Accepted and an empty body.
+	if resp.StatusCode == http.StatusAccepted {
+		return nil
+	}
+
+	switch contentType := mediaType(resp.Header.Get("Content-Type")); contentType {
+	case "text/event-stream":
+		return c.consumeEvents(resp.Body)
+	default:
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+		msg, err := jsonrpc.DecodeMessage(body)
+		if err != nil {
+			return fmt.Errorf("failed to decode response body: %w", err)
+		}
+		c.incoming <- msg
+		return nil
+	}
+}
+
+// runEventStream opens the long-lived GET SSE channel the server uses to
+// push messages unprompted by a POST (e.g. server-to-client requests and
+// notifications), resuming from lastEventID if one was supplied.
+func (c *StreamableHTTPConnection) runEventStream(ctx context.Context) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		c.errCh <- fmt.Errorf("failed to build event stream request: %w", err)
+		return
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if lastEventID := c.LastEventID(); lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// The server may not support the GET stream at all; POST responses
+		// still work, so this is not fatal to the connection.
+		return
+	}
+	defer resp.Body.Close()
+
+	// 405 Method Not Allowed is the spec's way of saying this endpoint has
+	// no server-push stream; nothing more to do here.
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+
+	if sessionID := resp.Header.Get("Mcp-Session-Id"); sessionID != "" {
+		c.mu.Lock()
+		c.sessionID = sessionID
+		c.mu.Unlock()
+	}
+
+	_ = c.consumeEvents(resp.Body)
+}
+
+// consumeEvents parses an SSE stream, decoding each "data:" event as a
+// JSON-RPC message and pushing it onto incoming, and tracking "id:" fields
+// as the resumability cursor.
+func (c *StreamableHTTPConnection) consumeEvents(body io.Reader) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var data strings.Builder
+	flush := func() {
+		if data.Len() == 0 {
+			return
+		}
+		defer data.Reset()
+		msg, err := jsonrpc.DecodeMessage([]byte(data.String()))
+		if err != nil {
+			return
+		}
+		select {
+		case c.incoming <- msg:
+		case <-c.closeCh:
+		}
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "id:"):
+			c.mu.Lock()
+			c.lastEventID = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+			c.mu.Unlock()
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	flush()
+	return scanner.Err()
+}
+
+// Close stops this connection's event stream and marks it unusable for
+// further reads.
+func (c *StreamableHTTPConnection) Close() error {
+	c.closeOnce.Do(func() { close(c.closeCh) })
+	return nil
+}
+
+// mediaType strips any "; charset=..." suffix from a Content-Type header.
+func mediaType(contentType string) string {
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return strings.TrimSpace(contentType)
+}
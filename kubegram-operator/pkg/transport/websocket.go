@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"sync"
 	"time"
 
@@ -20,6 +22,31 @@ type WebSocketTransport struct {
 	conn    *websocket.Conn
 	stopCh  chan struct{}
 	handler func(jsonrpc.Message)
+
+	// ProxyURL, if set, is used instead of the environment's
+	// HTTP_PROXY/HTTPS_PROXY/NO_PROXY (via http.ProxyFromEnvironment) to
+	// reach URL. Supports http(s):// and socks5:// schemes; see
+	// ProxyDialContext.
+	ProxyURL *url.URL
+
+	// Header, if set, is sent on the handshake request for every dial
+	// (initial connect and each reconnect). Used to carry an
+	// "Authorization: Bearer <token>" header the way WithSSEBearerToken
+	// does for the SSE transport; see WithWebSocketBearerToken.
+	Header http.Header
+
+	// AuthChecker, if set, re-validates this transport's session
+	// credentials/scopes on a fixed interval; see SetAuthChecker.
+	AuthChecker *AuthChecker
+}
+
+// proxyFunc returns t.ProxyURL as a fixed proxy, or falls back to honoring
+// HTTP_PROXY/HTTPS_PROXY/NO_PROXY from the environment.
+func (t *WebSocketTransport) proxyFunc() func(*http.Request) (*url.URL, error) {
+	if t.ProxyURL != nil {
+		return http.ProxyURL(t.ProxyURL)
+	}
+	return http.ProxyFromEnvironment
 }
 
 // NewWebSocketTransport creates a new WebSocket transport
@@ -30,12 +57,29 @@ func NewWebSocketTransport(url string) *WebSocketTransport {
 	}
 }
 
+// SetAuthChecker configures periodic re-authorization for the sessions
+// this transport opens: every checker.Interval, checker.Check is called
+// and its result compared against checker.Template (the SessionAuth
+// captured when the session was opened). If they differ in any field —
+// token, user id, allowed tool prefixes, expiry — or checker.Check errors
+// (e.g. the authorization endpoint returned non-200), Start tears down the
+// current *websocket.Conn and redials immediately, so the next connection
+// picks up fresh credentials. A fresh copy of checker is armed for every
+// connection Start makes; call SetAuthChecker again (with an updated
+// Template) if the caller obtains new credentials. Call it before Start.
+func (t *WebSocketTransport) SetAuthChecker(checker *AuthChecker) {
+	t.mu.Lock()
+	t.AuthChecker = checker
+	t.mu.Unlock()
+}
+
 // Connect dial the websocket and returns a connection
 func (t *WebSocketTransport) Connect(ctx context.Context) (mcp.Connection, error) {
 	dialer := websocket.Dialer{
 		HandshakeTimeout: 10 * time.Second,
+		NetDialContext:   ProxyDialContext(t.proxyFunc()),
 	}
-	conn, _, err := dialer.DialContext(ctx, t.URL, nil)
+	conn, _, err := dialer.DialContext(ctx, t.URL, t.Header)
 	if err != nil {
 		return nil, err
 	}
@@ -47,8 +91,9 @@ func (t *WebSocketTransport) Connect(ctx context.Context) (mcp.Connection, error
 func (t *WebSocketTransport) connect(ctx context.Context) error {
 	dialer := websocket.Dialer{
 		HandshakeTimeout: 10 * time.Second,
+		NetDialContext:   ProxyDialContext(t.proxyFunc()),
 	}
-	conn, _, err := dialer.DialContext(ctx, t.URL, nil)
+	conn, _, err := dialer.DialContext(ctx, t.URL, t.Header)
 	if err != nil {
 		return err
 	}
@@ -103,6 +148,12 @@ func (t *WebSocketTransport) Start(ctx context.Context, handler func(jsonrpc.Mes
 			connectedAt := time.Now()
 			logger.Info("Connected to WebSocket server")
 
+			// Arm a fresh AuthChecker for this connection, if configured.
+			var authChangedCh <-chan error
+			if t.AuthChecker != nil {
+				authChangedCh = t.AuthChecker.Clone().Start(ctx)
+			}
+
 			// Read loop
 			done := make(chan struct{})
 			go func() {
@@ -142,7 +193,7 @@ func (t *WebSocketTransport) Start(ctx context.Context, handler func(jsonrpc.Mes
 			select {
 			case <-done:
 				logger.Info("Connection closed, reconnecting...")
-				t.Close() // Close the old connection before attempting to reconnect
+				t.closeConn() // Close the old connection before attempting to reconnect
 
 				// Check if connection was stable long enough to reset backoff
 				if time.Since(connectedAt) > resetWindow {
@@ -161,6 +212,14 @@ func (t *WebSocketTransport) Start(ctx context.Context, handler func(jsonrpc.Mes
 					}
 				}
 
+			case err := <-authChangedCh:
+				// The session's credentials/scopes no longer match what it
+				// was opened with (or the recheck itself failed); tear down
+				// and redial immediately so the next connection picks up
+				// fresh ones, without applying reconnect backoff.
+				logger.Info("Session authorization changed, reconnecting", "reason", err)
+				t.closeConn()
+
 			case <-ctx.Done():
 				t.Close()
 				return nil
@@ -172,18 +231,30 @@ func (t *WebSocketTransport) Start(ctx context.Context, handler func(jsonrpc.Mes
 	}
 }
 
-// Close closes the underlying websocket connection and stops the transport.
-func (t *WebSocketTransport) Close() error {
+// closeConn closes the underlying websocket connection, if any, without
+// signaling Start's loop to stop — used for reconnects (a dropped
+// connection, or a changed AuthChecker) that should redial rather than end
+// the transport. Close is the public, full-stop variant.
+func (t *WebSocketTransport) closeConn() error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
 	if t.conn != nil {
 		err := t.conn.Close()
-		t.conn = nil // Clear the connection
+		t.conn = nil
 		if err != nil {
 			return fmt.Errorf("failed to close websocket connection: %w", err)
 		}
 	}
+	return nil
+}
+
+// Close closes the underlying websocket connection and stops the transport.
+func (t *WebSocketTransport) Close() error {
+	err := t.closeConn()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	// Signal to stop the Start loop if it's running
 	select {
 	case <-t.stopCh:
@@ -191,7 +262,7 @@ func (t *WebSocketTransport) Close() error {
 	default:
 		close(t.stopCh)
 	}
-	return nil
+	return err
 }
 
 // WebSocketConnection implements mcp.Connection
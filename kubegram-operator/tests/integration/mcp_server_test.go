@@ -13,7 +13,6 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/kubegram/kubegram-operator/pkg/mcp"
 	"github.com/kubegram/kubegram-operator/pkg/transport"
-	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
 	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -226,48 +225,6 @@ func TestMCPServerInitialization(t *testing.T) {
 	}
 }
 
-// PipeTransport implements mcp.Transport using net.Pipe
-type PipeTransport struct {
-	conn net.Conn
-}
-
-func (t *PipeTransport) Connect(ctx context.Context) (sdk.Connection, error) {
-	return &PipeConnection{conn: t.conn}, nil
-}
-
-// PipeConnection implements mcp.Connection
-type PipeConnection struct {
-	conn net.Conn
-}
-
-func (c *PipeConnection) SessionID() string { return "pipe-session" }
-func (c *PipeConnection) Close() error      { return c.conn.Close() }
-func (c *PipeConnection) Read(ctx context.Context) (jsonrpc.Message, error) {
-	// Simple JSON-RPC reading - we assume 1 JSON object per Write
-	// net.Pipe guarantees atomic writes? No.
-	// We need a framer or decoder.
-	// For test, we can use json.Decoder. It handles standard stream parsing.
-	decoder := json.NewDecoder(c.conn)
-	// We need to decode into something generic first or directly using sdk helpers?
-	// jsonrpc.DecodeMessage takes []byte.
-	var raw json.RawMessage
-	if err := decoder.Decode(&raw); err != nil {
-		return nil, err
-	}
-	return jsonrpc.DecodeMessage(raw)
-}
-
-func (c *PipeConnection) Write(ctx context.Context, message jsonrpc.Message) error {
-	data, err := jsonrpc.EncodeMessage(message)
-	if err != nil {
-		return err
-	}
-	// We need to send it as a distinct JSON object. Content is already JSON.
-	// Just write it.
-	_, err = c.conn.Write(data)
-	return err
-}
-
 func TestMCPProxiedToolsAggregation(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -298,7 +255,7 @@ func TestMCPProxiedToolsAggregation(t *testing.T) {
 		c1, c2 := net.Pipe()
 
 		// Run server in background
-		go server.Run(ctx, &PipeTransport{conn: c1})
+		go server.Run(ctx, transport.NewPipeTransport(c1))
 
 		// Create Client to connect to it
 		client := sdk.NewClient(&sdk.Implementation{
@@ -306,7 +263,7 @@ func TestMCPProxiedToolsAggregation(t *testing.T) {
 			Version: "1.0",
 		}, nil)
 
-		session, err := client.Connect(ctx, &PipeTransport{conn: c2}, nil)
+		session, err := client.Connect(ctx, transport.NewPipeTransport(c2), nil)
 		if err != nil {
 			t.Fatalf("Failed to connect to mock %s: %v", name, err)
 		}
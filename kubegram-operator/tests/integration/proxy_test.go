@@ -0,0 +1,143 @@
+package integration
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/kubegram/kubegram-operator/pkg/transport"
+)
+
+// startStubConnectProxy starts a minimal HTTP CONNECT proxy that tunnels any
+// CONNECT request straight to its target address, and returns the proxy's
+// listen address.
+func startStubConnectProxy(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start stub proxy: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go handleStubConnect(conn)
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func handleStubConnect(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	req, err := http.ReadRequest(reader)
+	if err != nil || req.Method != http.MethodConnect {
+		return
+	}
+
+	upstream, err := net.Dial("tcp", req.Host)
+	if err != nil {
+		fmt.Fprintf(conn, "HTTP/1.1 502 Bad Gateway\r\n\r\n")
+		return
+	}
+	defer upstream.Close()
+
+	fmt.Fprintf(conn, "HTTP/1.1 200 Connection Established\r\n\r\n")
+
+	done := make(chan struct{}, 2)
+	go func() { io.Copy(upstream, reader); done <- struct{}{} }()
+	go func() { io.Copy(conn, upstream); done <- struct{}{} }()
+	<-done
+}
+
+// TestProxyDialContextThroughCONNECTProxy verifies transport.ProxyDialContext
+// actually tunnels a connection through an HTTP CONNECT proxy rather than
+// dialing the target directly.
+func TestProxyDialContextThroughCONNECTProxy(t *testing.T) {
+	targetLn, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start target listener: %v", err)
+	}
+	defer targetLn.Close()
+
+	accepted := make(chan struct{}, 1)
+	go func() {
+		conn, err := targetLn.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		accepted <- struct{}{}
+	}()
+
+	proxyAddr := startStubConnectProxy(t)
+	proxyURL, err := url.Parse("http://" + proxyAddr)
+	if err != nil {
+		t.Fatalf("failed to parse stub proxy URL: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	dial := transport.ProxyDialContext(http.ProxyURL(proxyURL))
+	conn, err := dial(ctx, "tcp", targetLn.Addr().String())
+	if err != nil {
+		t.Fatalf("dial through CONNECT proxy failed: %v", err)
+	}
+	defer conn.Close()
+
+	select {
+	case <-accepted:
+	case <-time.After(2 * time.Second):
+		t.Fatal("target never saw a connection, so the dial didn't actually tunnel through the proxy")
+	}
+}
+
+// TestProxyDialContextNoProxy verifies that a proxyFunc returning a nil URL
+// (the "NO_PROXY"/unconfigured case) dials the target directly.
+func TestProxyDialContextNoProxy(t *testing.T) {
+	targetLn, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start target listener: %v", err)
+	}
+	defer targetLn.Close()
+
+	accepted := make(chan struct{}, 1)
+	go func() {
+		conn, err := targetLn.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		accepted <- struct{}{}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	dial := transport.ProxyDialContext(func(*http.Request) (*url.URL, error) { return nil, nil })
+	conn, err := dial(ctx, "tcp", targetLn.Addr().String())
+	if err != nil {
+		t.Fatalf("direct dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	select {
+	case <-accepted:
+	case <-time.After(2 * time.Second):
+		t.Fatal("target never saw a connection")
+	}
+}